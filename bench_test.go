@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// workspace sizes each benchmark runs at
+var benchSizes = []int{10, 50, 300}
+
+const benchPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+    <modelVersion>4.0.0</modelVersion>
+    <parent>
+        <groupId>com.example.bench</groupId>
+        <artifactId>bench-parent</artifactId>
+        <version>1.0.0</version>
+    </parent>
+    <groupId>com.example.bench</groupId>
+    <artifactId>%s</artifactId>
+    <version>1.0.0-SNAPSHOT</version>
+</project>
+`
+
+// runs git with args in dir, used to build the synthetic repos
+func benchGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	_, err := runCmd(cmd)
+	return err
+}
+
+// creates n git repos with a committed pom.xml under a temp dir and makes
+// it the working directory, the way enterWorkspace does
+func setupBench(b *testing.B, n int) []Repo {
+	b.Helper()
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		b.Setenv(env, "massgit bench")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		b.Setenv(env, "bench@massgit.invalid")
+	}
+	dir := b.TempDir()
+
+	repos := make([]Repo, n)
+	errs := make([]error, n)
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			name := fmt.Sprintf("bench-%04d", i)
+			repoPath := filepath.Join(dir, name)
+			repos[i] = Repo{Name: name, Selected: true}
+			if errs[i] = os.MkdirAll(repoPath, 0755); errs[i] != nil {
+				return
+			}
+			if errs[i] = benchGit(repoPath, "init", "-q", "-b", "master"); errs[i] != nil {
+				return
+			}
+			if errs[i] = os.WriteFile(filepath.Join(repoPath, "pom.xml"), []byte(fmt.Sprintf(benchPom, name)), 0644); errs[i] != nil {
+				return
+			}
+			if errs[i] = benchGit(repoPath, "add", "pom.xml"); errs[i] != nil {
+				return
+			}
+			errs[i] = benchGit(repoPath, "commit", "-q", "-m", "initial pom")
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			b.Fatalf("generating repos: %v", err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		b.Fatal(err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { os.Chdir(wd) })
+	return repos
+}
+
+// runs op on every repo concurrently the way the tui does, failing the
+// benchmark on the first repo error
+func forEachRepo(b *testing.B, repos []Repo, op func(repo *Repo)) {
+	var wg sync.WaitGroup
+	for i := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			op(&repos[i])
+		}()
+	}
+	wg.Wait()
+	for _, repo := range repos {
+		if repo.LastError != "" {
+			b.Fatalf("%s: %s", repo.Name, repo.LastError)
+		}
+	}
+}
+
+// runs fn as a sub-benchmark over a generated workspace of each size, the
+// repos are loaded once before fn
+func benchEachSize(b *testing.B, fn func(b *testing.B, repos []Repo)) {
+	for _, n := range benchSizes {
+		repos := setupBench(b, n)
+		forEachRepo(b, repos, func(repo *Repo) { updateRepo(repo, &MessageAccumulator{}, false) })
+		b.Run(fmt.Sprintf("repos=%d", n), func(b *testing.B) { fn(b, repos) })
+	}
+}
+
+func BenchmarkReload(b *testing.B) {
+	b.Run("cold", func(b *testing.B) {
+		benchEachSize(b, func(b *testing.B, repos []Repo) {
+			for range b.N {
+				ma := &MessageAccumulator{}
+				forEachRepo(b, repos, func(repo *Repo) { updateRepo(repo, ma, false) })
+			}
+		})
+	})
+	b.Run("warm", func(b *testing.B) {
+		benchEachSize(b, func(b *testing.B, repos []Repo) {
+			for range b.N {
+				ma := &MessageAccumulator{}
+				forEachRepo(b, repos, func(repo *Repo) { updateRepo(repo, ma, true) })
+			}
+		})
+	})
+}
+
+// moves the config to the version the repos are not at, so every save
+// rewrites the poms
+func toggleBenchVersion(config *Config) {
+	config.Version, config.ParentVersion = "1.1.0-SNAPSHOT", "1.1.0"
+	if config.Repos[0].Maven.Version == config.Version {
+		config.Version, config.ParentVersion = "1.0.0-SNAPSHOT", "1.0.0"
+	}
+}
+
+func BenchmarkSave(b *testing.B) {
+	benchEachSize(b, func(b *testing.B, repos []Repo) {
+		config := &Config{Repos: repos, Branch: "bench"}
+		for range b.N {
+			ma := &MessageAccumulator{}
+			toggleBenchVersion(config)
+			forEachRepo(b, repos, func(repo *Repo) { saveRepo(repo, config, false, ma) })
+		}
+	})
+}
+
+func BenchmarkCommit(b *testing.B) {
+	benchEachSize(b, func(b *testing.B, repos []Repo) {
+		config := &Config{Repos: repos, Branch: "bench"}
+		for range b.N {
+			b.StopTimer()
+			toggleBenchVersion(config)
+			forEachRepo(b, repos, func(repo *Repo) { saveRepo(repo, config, false, &MessageAccumulator{}) })
+			b.StartTimer()
+
+			forEachRepo(b, repos, func(repo *Repo) {
+				repoPath := fmt.Sprintf("./%s", repo.Name)
+				_, err := gitAdd(repoPath, versionFiles(repoPath)...)
+				if err == nil {
+					_, err = gitCommit(repoPath, "update pom version")
+				}
+				repo.setError(err)
+			})
+		}
+	})
+}
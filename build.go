@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultBuildCommand = "mvn -o install"
+	// output lines kept per repo
+	buildBacklog = 2000
+	// output lines shown for the selected repo
+	buildPage = 15
+	// output lines copied into the repo's error on failure
+	buildErrorLines = 20
+)
+
+func (c Config) buildCommand() string {
+	if c.BuildCommand == "" {
+		return defaultBuildCommand
+	}
+	return c.BuildCommand
+}
+
+type buildStatus uint
+
+const (
+	buildWaiting buildStatus = iota
+	buildRunning
+	buildOk
+	buildFailed
+	buildSkipped
+)
+
+func (s buildStatus) String() string {
+	switch s {
+	case buildWaiting:
+		return "⏳"
+	case buildRunning:
+		return "🔨"
+	case buildOk:
+		return "✅"
+	case buildFailed:
+		return "❌"
+	default:
+		return "⏭ "
+	}
+}
+
+type buildRepo struct {
+	idx     int
+	name    string
+	deps    []string
+	status  buildStatus
+	output  []string
+	err     error
+	start   time.Time
+	elapsed time.Duration
+}
+
+// a line of output or, when done is set, the end of a repo's build
+type buildMsg struct {
+	name string
+	line string
+	done bool
+	err  error
+}
+
+// orders repos so every repo comes after the repos it depends on, failing
+// on a cycle since none of the repos in it could ever start
+func topoOrder(repos []string, deps map[string][]string) ([]string, error) {
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, name := range repos {
+		pending[name] = len(deps[name])
+		for _, dep := range deps[name] {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+	var order, ready []string
+	for _, name := range repos {
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(order) < len(repos) {
+		var cycle []string
+		for _, name := range repos {
+			if pending[name] > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
+
+// BuildModel runs the build command in the selected repos in dependency
+// order, building independent repos in parallel and skipping the
+// dependents of a failed build
+type BuildModel struct {
+	config  *Config
+	repos   []buildRepo
+	err     error
+	running bool
+	aborted bool
+	events  chan buildMsg
+	ctx     context.Context
+	cancel  context.CancelFunc
+	cursor  int
+	offset  int
+	start   time.Time
+}
+
+func NewBuild(config *Config) BuildModel {
+	return BuildModel{
+		config: config,
+	}
+}
+
+// plans a build of the selected repos, a running build is left alone
+func (m BuildModel) reset() BuildModel {
+	if m.running {
+		return m
+	}
+	var selected []Repo
+	for _, repo := range m.config.Repos {
+		if repo.Selected {
+			selected = append(selected, repo)
+		}
+	}
+	graph := buildDepGraph(selected)
+	deps := map[string][]string{}
+	for _, edge := range graph.edges {
+		if !slices.Contains(deps[edge.from], edge.to) {
+			deps[edge.from] = append(deps[edge.from], edge.to)
+		}
+	}
+
+	m.repos = nil
+	m.cursor = 0
+	m.offset = 0
+	m.aborted = false
+	var order []string
+	order, m.err = topoOrder(graph.repos, deps)
+	for _, name := range order {
+		idx := slices.IndexFunc(m.config.Repos, func(r Repo) bool { return r.Name == name })
+		m.repos = append(m.repos, buildRepo{idx: idx, name: name, deps: deps[name]})
+	}
+	return m
+}
+
+func (m BuildModel) find(name string) int {
+	return slices.IndexFunc(m.repos, func(r buildRepo) bool { return r.name == name })
+}
+
+// starts every waiting repo whose dependencies built and skips those with
+// a dependency that didn't, finishing the run once nothing is left
+func (m BuildModel) schedule() (BuildModel, tea.Cmd) {
+	var cmds []tea.Cmd
+	for i := range m.repos {
+		r := &m.repos[i]
+		if r.status != buildWaiting {
+			continue
+		}
+		if m.aborted {
+			r.status = buildSkipped
+			r.err = errors.New("build aborted")
+			continue
+		}
+		ready := true
+		for _, dep := range r.deps {
+			switch m.repos[m.find(dep)].status {
+			case buildFailed, buildSkipped:
+				r.status = buildSkipped
+				r.err = fmt.Errorf("dependency %s did not build", dep)
+			case buildWaiting, buildRunning:
+				ready = false
+			}
+		}
+		if r.status == buildSkipped || !ready {
+			continue
+		}
+		r.status = buildRunning
+		r.start = time.Now()
+		logInfo("building %s", r.name)
+		cmds = append(cmds, m.run(r.name))
+	}
+
+	if !slices.ContainsFunc(m.repos, func(r buildRepo) bool { return r.status == buildRunning }) {
+		m = m.finish()
+		return m, nil
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// runs the build command in repo, sending its output line by line
+func (m BuildModel) run(name string) tea.Cmd {
+	ctx, events, command := m.ctx, m.events, m.config.buildCommand()
+	return func() tea.Msg {
+		cmd := exec.CommandContext(ctx, shell, "-c", command)
+		cmd.Dir = fmt.Sprintf("./%s", name)
+		reader, writer := io.Pipe()
+		cmd.Stdout = writer
+		cmd.Stderr = writer
+		// processes the shell started can hold the pipe open after an abort
+		cmd.WaitDelay = time.Second
+
+		err := cmd.Start()
+		if err != nil {
+			events <- buildMsg{name: name, done: true, err: err}
+			return nil
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- cmd.Wait()
+			writer.Close()
+		}()
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			events <- buildMsg{name: name, line: scanner.Text()}
+		}
+		// drain whatever is left after a line too long to scan
+		io.Copy(io.Discard, reader)
+		events <- buildMsg{name: name, done: true, err: <-done}
+		return nil
+	}
+}
+
+// waits for the next line or finished build of any repo
+func (m BuildModel) wait() tea.Cmd {
+	events := m.events
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+func (m BuildModel) finish() BuildModel {
+	if !m.running {
+		return m
+	}
+	m.running = false
+	m.cancel()
+	m.cancel = nil
+
+	outcomes := make([]RepoOutcome, 0, len(m.repos))
+	failed := 0
+	for _, r := range m.repos {
+		outcome := RepoOutcome{Name: r.name, Ok: r.status == buildOk, Elapsed: r.elapsed.Milliseconds()}
+		if r.err != nil {
+			outcome.Error = r.err.Error()
+			failed++
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	recordHistory("build", m.start, outcomes)
+	logInfo("built %d repos with %d failed or skipped in %dms", len(m.repos), failed, time.Since(m.start).Milliseconds())
+	return m
+}
+
+func (m BuildModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m BuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case buildMsg:
+		i := m.find(msg.name)
+		if i < 0 || !m.running {
+			return m, nil
+		}
+		r := &m.repos[i]
+		if !msg.done {
+			r.output = append(r.output, msg.line)
+			if len(r.output) > buildBacklog {
+				r.output = r.output[len(r.output)-buildBacklog:]
+			}
+			return m, m.wait()
+		}
+
+		r.elapsed = time.Since(r.start)
+		repo := &m.config.Repos[r.idx]
+		if msg.err != nil {
+			r.status = buildFailed
+			r.err = msg.err
+			tail := r.output[max(len(r.output)-buildErrorLines, 0):]
+			repo.setError(fmt.Errorf("build failed: %w\n%s", msg.err, strings.Join(tail, "\n")))
+			logError("failed to build %s, err=%v", r.name, msg.err)
+		} else {
+			r.status = buildOk
+			repo.setError(nil)
+			logInfo("built %s in %dms", r.name, r.elapsed.Milliseconds())
+		}
+		var cmd tea.Cmd
+		m, cmd = m.schedule()
+		if m.running {
+			return m, tea.Batch(cmd, m.wait())
+		}
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			if m.running {
+				m.cancel()
+			}
+			return m, tea.Quit
+		case "esc", "B":
+			m.config.state = homeView
+			return m, tea.ClearScreen
+		case "up", "k":
+			m.cursor = positiveMod(m.cursor-1, max(len(m.repos), 1))
+			m.offset = 0
+		case "down", "j":
+			m.cursor = positiveMod(m.cursor+1, max(len(m.repos), 1))
+			m.offset = 0
+		case "u", "pgup":
+			if m.cursor < len(m.repos) {
+				m.offset = min(m.offset+buildPage, max(len(m.repos[m.cursor].output)-buildPage, 0))
+			}
+		case "d", "pgdown":
+			m.offset = max(m.offset-buildPage, 0)
+		case "x":
+			if m.running {
+				logWarn("aborting build")
+				m.aborted = true
+				m.cancel()
+			}
+		case "enter", " ":
+			if m.running || m.err != nil || len(m.repos) == 0 {
+				return m, nil
+			}
+			if m.repos[0].status != buildWaiting {
+				m = m.reset()
+			}
+			m.running = true
+			m.start = time.Now()
+			m.events = make(chan buildMsg, 256)
+			m.ctx, m.cancel = context.WithCancel(context.Background())
+			logInfo("building %d repos with %q", len(m.repos), m.config.buildCommand())
+			var cmd tea.Cmd
+			m, cmd = m.schedule()
+			m.config.refreshGen++
+			return m, tea.Batch(cmd, m.wait())
+		}
+	}
+	return m, nil
+}
+
+func (m BuildModel) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("Can't build: %v\n", m.err) + helpStyle.Render("\nesc: back • q: exit\n")
+	}
+	if len(m.repos) == 0 {
+		return "No repos selected.\n" + helpStyle.Render("\nesc: back • q: exit\n")
+	}
+
+	s := fmt.Sprintf("Build with %q in dependency order\n\n", m.config.buildCommand())
+	for i, r := range m.repos {
+		line := fmt.Sprintf("%s %s", r.status, strings.TrimPrefix(r.name, m.config.Prefix))
+		switch r.status {
+		case buildRunning:
+			line += fmt.Sprintf("  %ds", int(time.Since(r.start).Seconds()))
+		case buildOk, buildFailed:
+			line += fmt.Sprintf("  %dms", r.elapsed.Milliseconds())
+		}
+		if r.status == buildWaiting && len(r.deps) > 0 {
+			var deps []string
+			for _, dep := range r.deps {
+				deps = append(deps, strings.TrimPrefix(dep, m.config.Prefix))
+			}
+			line += helpStyle.Render(fmt.Sprintf("  after %s", strings.Join(deps, ", ")))
+		}
+		if r.err != nil {
+			line += "  " + conflictStyle.Render(firstLine(r.err))
+		}
+		if i == m.cursor {
+			line = selectedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+		s += line + "\n"
+	}
+
+	if m.cursor < len(m.repos) {
+		r := m.repos[m.cursor]
+		end := max(len(r.output)-m.offset, 0)
+		lines := r.output[max(end-buildPage, 0):end]
+		s += fmt.Sprintf("\n%s output:\n", strings.TrimPrefix(r.name, m.config.Prefix))
+		s += logStyle.Render(strings.Join(lines, "\n"))
+		s += "\n"
+	}
+
+	if m.running {
+		s += helpStyle.Render("\njk: select repo • u/d: scroll output • x: abort • esc: back • q: exit\n")
+	} else {
+		s += helpStyle.Render("\nenter: build • jk: select repo • u/d: scroll output • esc: back • q: exit\n")
+	}
+	return s
+}
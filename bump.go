@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m SettingsModel) updateBump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		if m.state == bumpPreviewView {
+			m.state = bumpView
+		} else {
+			m.state = repoView
+		}
+	case "up", "k":
+		if m.state == bumpView {
+			m.bumpCursor = positiveMod(m.bumpCursor-1, len(bumpActions))
+		}
+	case "down", "j":
+		if m.state == bumpView {
+			m.bumpCursor = positiveMod(m.bumpCursor+1, len(bumpActions))
+		}
+	case "enter", " ":
+		if m.state == bumpView {
+			m.bump = planBump(m.config, bumpActions[m.bumpCursor])
+			m.state = bumpPreviewView
+			return m, nil
+		}
+		for i := range m.config.Repos {
+			repo := &m.config.Repos[i]
+			repo.targetVersion = m.bump.versions[repo.Name]
+			// repos the plan skipped keep the version they are at
+			if _, skipped := m.bump.errs[repo.Name]; skipped {
+				repo.targetVersion = repo.Maven.Version
+			}
+		}
+		m.config.Version = m.bump.common()
+		logInfo("applying %s bump to %d repos", m.bump.action, len(m.bump.versions))
+		m.state = savingView
+		m.msg = "saving..."
+		return m, func() tea.Msg { return saveMsg{} }
+	}
+	return m, nil
+}
+
+func (m SettingsModel) viewBump() string {
+	var s string
+	if m.state == bumpView {
+		s += "Bump the version of the selected repos:\n\n"
+		for i, action := range bumpActions {
+			cursor := " "
+			if i == m.bumpCursor {
+				cursor = selectedStyle.Render(">")
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, action.name)
+		}
+		s += helpStyle.Render("\nenter: preview • esc: back\n")
+		return s
+	}
+
+	s += fmt.Sprintf("%s bump:\n\n", m.bump.action)
+	for _, repo := range m.config.Repos {
+		if !repo.Selected {
+			continue
+		}
+		if err, ok := m.bump.errs[repo.Name]; ok {
+			s += fmt.Sprintf("  %-30s skipped: %v\n", repo.Name, err)
+			continue
+		}
+		s += fmt.Sprintf("  %-30s %s -> %s\n", repo.Name, repo.Maven.Version, m.bump.versions[repo.Name])
+	}
+	if len(m.bump.propagations) > 0 {
+		s += "\ndependents updated:\n\n"
+		for _, p := range m.bump.propagations {
+			s += fmt.Sprintf("  %s\n", p)
+		}
+	}
+	s += helpStyle.Render("\nenter: apply and save • esc: back\n")
+	return s
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// cmdError is returned by runCmd when a command fails, keeping the stderr
+// the command wrote so callers can show more than "exit status 128"
+type cmdError struct {
+	args   []string
+	stderr string
+	err    error
+}
+
+func (e *cmdError) Error() string {
+	stderr := strings.TrimSpace(e.stderr)
+	if stderr == "" {
+		return fmt.Sprintf("%s: %v", strings.Join(e.args, " "), e.err)
+	}
+	return fmt.Sprintf("%s: %v\n%s", strings.Join(e.args, " "), e.err, stderr)
+}
+
+func (e *cmdError) Unwrap() error {
+	return e.err
+}
+
+// runs cmd and returns its stdout, attaching stderr to the error on failure
+func runCmd(cmd *exec.Cmd) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, &cmdError{args: cmd.Args, stderr: stderr.String(), err: err}
+	}
+	return out, nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	configDir  = ".massgit"
+	configFile = ".massgit/config.json"
+	backupDir  = ".massgit/backups"
+	// previous configs kept in backupDir, newest is config.json.1
+	maxConfigBackups = 5
+)
+
+// serializes config writes so a save can't interleave with another one
+var configMu sync.Mutex
+
+type Config struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Repos         []Repo `json:"repos"`
+	VisibleRepos  []int  `json:"visibleRepos"`
+	Branch        string `json:"branch"`
+	Version       string `json:"version"`
+	ParentVersion string `json:"parentVersion"`
+	Prefix        string `json:"prefix"`
+	Cols          int    `json:"cols"`
+	// named selection/branch/version sets, see Profile
+	Profiles      map[string]Profile `json:"profiles"`
+	ActiveProfile string             `json:"activeProfile"`
+	// how often the grid reloads repo state in the background, e.g. "5m",
+	// empty turns it off
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// rerun status as soon as files change in a selected repo
+	Watch bool `json:"watch,omitempty"`
+	// name of release tags, see tagName
+	TagTemplate string `json:"tagTemplate,omitempty"`
+	// what saving an already released version does, see guardRelease
+	ReleaseGuard   string `json:"releaseGuard,omitempty"`
+	CheckLocalRepo bool   `json:"checkLocalRepo,omitempty"`
+	// rewrite the dependency versions of sibling repos when a version is
+	// saved, see propagateVersions
+	PropagateVersions bool `json:"propagateVersions,omitempty"`
+	// run in each repo by the build runner, see BuildModel
+	BuildCommand string `json:"buildCommand,omitempty"`
+	state        sessionState
+	// bumped whenever repos are changed in the foreground so background
+	// refreshes started before that are discarded
+	refreshGen int
+	// bumped whenever the refresh interval changes, see refreshTickMsg
+	refreshTick int
+	// per repo step timings of the last reload, refresh or save
+	timings timingRun
+	// workspace values of the layered settings and where each effective
+	// value came from, see applyLayers
+	stored  layeredSettings
+	sources map[string]valueSource
+}
+
+func (c Config) String() string {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return "failed"
+	}
+	return string(bytes)
+}
+
+func (c Config) save() error {
+	logDebug("saving config %s", c)
+	err := saveConfig(c.withActiveProfile().persisted())
+	if err != nil {
+		logError("failed to save config, err=%v", err)
+	}
+	return err
+}
+
+// parses a config of any supported schema version, upgrading it on the way
+func parseConfig(bytes []byte) (Config, error) {
+	config, _, err := parseAndMigrateConfig(bytes)
+	return config, err
+}
+
+func parseAndMigrateConfig(bytes []byte) (Config, bool, error) {
+	var config Config
+	bytes, version, err := migrateConfig(bytes)
+	if err != nil {
+		return config, false, err
+	}
+	err = json.Unmarshal(bytes, &config)
+	return config, version != configSchemaVersion, err
+}
+
+func getConfig() (Config, bool) {
+	var config Config
+
+	_, err := os.ReadDir(configDir)
+	if err != nil {
+		logInfo(".massgit does not exist, err=%v", err)
+		return config, false
+	}
+
+	bytes, err := os.ReadFile(configFile)
+
+	if err != nil {
+		logInfo("config does not exist, err=%v", err)
+		return config, false
+	}
+
+	config, migrated, err := parseAndMigrateConfig(bytes)
+
+	if errors.Is(err, errNewerConfig) {
+		logError("refusing to load config, err=%v", err)
+		fmt.Printf("%v, upgrade massgit to use this workspace\n", err)
+		os.Exit(1)
+	} else if err != nil {
+		logError("failed to unmarshal config file, err=%v", err)
+		return recoverConfig()
+	}
+
+	if migrated {
+		logInfo("migrated config to schema version %d", configSchemaVersion)
+		err = saveConfig(config)
+		if err != nil {
+			logError("failed to save migrated config, err=%v", err)
+		}
+	}
+
+	return config, true
+}
+
+// moves a corrupt config aside and restores the newest backup that parses
+func recoverConfig() (Config, bool) {
+	corrupt := fmt.Sprintf("%s.corrupt-%d", configFile, time.Now().Unix())
+	err := os.Rename(configFile, corrupt)
+	if err != nil {
+		logError("failed to move corrupt config aside, err=%v", err)
+	} else {
+		logWarn("moved corrupt config to %s", corrupt)
+	}
+
+	for i := 1; i <= maxConfigBackups; i++ {
+		backup := backupName(i)
+		bytes, err := os.ReadFile(backup)
+		if err != nil {
+			continue
+		}
+		config, err := parseConfig(bytes)
+		if err != nil {
+			logWarn("backup %s is also corrupt, err=%v", backup, err)
+			continue
+		}
+		err = writeFileAtomic(configFile, bytes, 0644)
+		if err != nil {
+			logError("failed to restore %s, err=%v", backup, err)
+			return config, true
+		}
+		logWarn("restored config from %s", backup)
+		return config, true
+	}
+
+	logError("no usable config backup found, starting with a new config")
+	return Config{}, false
+}
+
+// sets up an empty config and the .massgit dir, the config itself is only
+// written when the wizard finishes so quitting it starts the wizard again
+func createConfig(config Config) (Config, error) {
+	config.Repos = []Repo{}
+	config.VisibleRepos = []int{}
+	config.Profiles = map[string]Profile{defaultProfile: {Selected: []string{}}}
+	config.ActiveProfile = defaultProfile
+
+	_, err := os.Stat(configDir)
+	if err == nil {
+		return config, nil
+	}
+
+	err = os.Mkdir(configDir, 0755)
+
+	if err != nil {
+		logError("failed to create massgit cache, err=%v", err)
+		return config, err
+	}
+
+	return config, nil
+}
+
+func saveConfig(config Config) error {
+	config.SchemaVersion = configSchemaVersion
+	bytes, err := json.Marshal(config)
+
+	if err != nil {
+		logError("failed to marshal config, err=%v", err)
+		return err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	err = backupConfig()
+
+	if err != nil {
+		logWarn("failed to back up config, err=%v", err)
+	}
+
+	err = writeFileAtomic(configFile, bytes, 0644)
+
+	if err != nil {
+		logError("failed to write config, err=%v", err)
+		return err
+	}
+
+	return nil
+}
+
+func backupName(n int) string {
+	return filepath.Join(backupDir, fmt.Sprintf("config.json.%d", n))
+}
+
+// copies the current config into the backup ring, a config that doesn't
+// parse is not worth keeping and would push out a good backup
+func backupConfig() error {
+	bytes, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, err = parseConfig(bytes); err != nil {
+		return nil
+	}
+
+	err = os.MkdirAll(backupDir, 0755)
+	if err != nil {
+		return err
+	}
+	os.Remove(backupName(maxConfigBackups))
+	for i := maxConfigBackups - 1; i > 0; i-- {
+		os.Rename(backupName(i), backupName(i+1))
+	}
+	return writeFileAtomic(backupName(1), bytes, 0644)
+}
+
+// writes to a temp file in the same dir and renames it over name, so a
+// crash leaves either the old or the new file but never a partial one
+func writeFileAtomic(name string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
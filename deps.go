@@ -0,0 +1,350 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const depsFile = ".massgit/deps.dot"
+
+// a dependency of one workspace repo on an artifact built by another
+type depEdge struct {
+	from     string
+	to       string
+	groupId  string
+	artifact string
+	version  string
+	// the version as written in the pom, before properties are expanded
+	raw     string
+	managed bool
+	// the edge is the <parent> of from rather than a dependency
+	parent bool
+}
+
+// depGraph links the selected repos through the dependencies and
+// dependencyManagement entries of their poms
+type depGraph struct {
+	repos []string
+	poms  map[string]Pom
+	edges []depEdge
+	errs  map[string]error
+}
+
+// buildDepGraph reads the poms of repos, selected or not, including the
+// poms of their modules
+func buildDepGraph(repos []Repo) depGraph {
+	g := depGraph{
+		poms: map[string]Pom{},
+		errs: map[string]error{},
+	}
+	artifacts := map[string]string{}
+	modulePoms := map[string][]Pom{}
+	for _, repo := range repos {
+		g.repos = append(g.repos, repo.Name)
+		repoPath := fmt.Sprintf("./%s", repo.Name)
+		pom, err := readRepoPom(repoPath)
+		if err != nil {
+			logDebug("failed to read pom for %s, err=%v", repo.Name, err)
+			g.errs[repo.Name] = err
+			continue
+		}
+		g.poms[repo.Name] = pom
+		artifacts[pom.key()] = repo.Name
+		modules, err := discoverModules(repoPath)
+		if err != nil {
+			g.errs[repo.Name] = err
+		}
+		for _, module := range modules {
+			modulePoms[repo.Name] = append(modulePoms[repo.Name], module.pom)
+			artifacts[module.pom.key()] = repo.Name
+		}
+	}
+
+	for _, name := range g.repos {
+		root, ok := g.poms[name]
+		if !ok {
+			continue
+		}
+		for _, pom := range append([]Pom{root}, modulePoms[name]...) {
+			g.addEdges(name, pom, artifacts)
+		}
+	}
+	return g
+}
+
+// adds the edges of one of the repo's poms, artifacts maps coordinates to
+// the repo building them
+func (g *depGraph) addEdges(name string, pom Pom, artifacts map[string]string) {
+	// modules of one repo often share dependencies
+	add := func(edge depEdge) {
+		if !slices.Contains(g.edges, edge) {
+			g.edges = append(g.edges, edge)
+		}
+	}
+	addDeps := func(deps []pomDependency, managed bool) {
+		for _, dep := range deps {
+			groupId := pom.expand(dep.GroupId)
+			to, ok := artifacts[groupId+":"+dep.ArtifactId]
+			if !ok || to == name {
+				continue
+			}
+			add(depEdge{
+				from:     name,
+				to:       to,
+				groupId:  groupId,
+				artifact: dep.ArtifactId,
+				version:  pom.expand(dep.Version),
+				raw:      strings.TrimSpace(dep.Version),
+				managed:  managed,
+			})
+		}
+	}
+	if to, ok := artifacts[pom.parentKey()]; ok && to != name {
+		add(depEdge{
+			from:     name,
+			to:       to,
+			groupId:  pom.Parent.GroupId,
+			artifact: pom.Parent.ArtifactId,
+			version:  pom.expand(strings.TrimSpace(pom.Parent.Version)),
+			raw:      strings.TrimSpace(pom.Parent.Version),
+			parent:   true,
+		})
+	}
+	addDeps(pom.Dependencies, false)
+	addDeps(pom.DependencyManagement, true)
+}
+
+// the edges of the repos name depends on
+func (g depGraph) dependsOn(name string) []depEdge {
+	var edges []depEdge
+	for _, edge := range g.edges {
+		if edge.from == name {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// the edges of the repos depending on name
+func (g depGraph) dependents(name string) []depEdge {
+	var edges []depEdge
+	for _, edge := range g.edges {
+		if edge.to == name {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// the graph in graphviz format, managed versions are drawn dashed and
+// parents bold
+func (g depGraph) dot(prefix string) string {
+	var b strings.Builder
+	b.WriteString("digraph massgit {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, name := range g.repos {
+		fmt.Fprintf(&b, "\t%q;\n", strings.TrimPrefix(name, prefix))
+	}
+	for _, edge := range g.edges {
+		attrs := fmt.Sprintf("label=%q", edge.version)
+		if edge.managed {
+			attrs += ", style=dashed"
+		} else if edge.parent {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(&b, "\t%q -> %q [%s];\n", strings.TrimPrefix(edge.from, prefix), strings.TrimPrefix(edge.to, prefix), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func exportDeps(g depGraph, prefix string) error {
+	err := os.MkdirAll(filepath.Dir(depsFile), 0755)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(depsFile, []byte(g.dot(prefix)), 0644)
+}
+
+type depsPane uint
+
+const (
+	reposPane depsPane = iota
+	usesPane
+	usedByPane
+)
+
+// DepsModel shows for each selected repo what it depends on and who
+// depends on it, enter on a dependency jumps to that repo
+type DepsModel struct {
+	config *Config
+	graph  depGraph
+	pane   depsPane
+	cursor int
+	edge   int
+	msg    string
+}
+
+func NewDeps(config *Config) DepsModel {
+	return DepsModel{
+		config: config,
+	}
+}
+
+// rereads the poms each time the view is opened
+func (m DepsModel) reload() DepsModel {
+	var selected []Repo
+	for _, repo := range m.config.Repos {
+		if repo.Selected {
+			selected = append(selected, repo)
+		}
+	}
+	m.graph = buildDepGraph(selected)
+	m.pane = reposPane
+	m.cursor = 0
+	m.edge = 0
+	m.msg = ""
+	return m
+}
+
+func (m DepsModel) current() string {
+	if m.cursor >= len(m.graph.repos) {
+		return ""
+	}
+	return m.graph.repos[m.cursor]
+}
+
+func (m DepsModel) paneEdges() []depEdge {
+	switch m.pane {
+	case usesPane:
+		return m.graph.dependsOn(m.current())
+	case usedByPane:
+		return m.graph.dependents(m.current())
+	}
+	return nil
+}
+
+func (m DepsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m DepsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "D":
+		m.config.state = homeView
+		return m, tea.ClearScreen
+	case "tab":
+		m.pane = (m.pane + 1) % 3
+		m.edge = 0
+	case "shift+tab":
+		m.pane = (m.pane + 2) % 3
+		m.edge = 0
+	case "up", "k":
+		if m.pane == reposPane {
+			m.cursor = positiveMod(m.cursor-1, max(len(m.graph.repos), 1))
+		} else {
+			m.edge = positiveMod(m.edge-1, max(len(m.paneEdges()), 1))
+		}
+	case "down", "j":
+		if m.pane == reposPane {
+			m.cursor = positiveMod(m.cursor+1, max(len(m.graph.repos), 1))
+		} else {
+			m.edge = positiveMod(m.edge+1, max(len(m.paneEdges()), 1))
+		}
+	case "enter", " ":
+		edges := m.paneEdges()
+		if m.edge >= len(edges) {
+			return m, nil
+		}
+		target := edges[m.edge].to
+		if m.pane == usedByPane {
+			target = edges[m.edge].from
+		}
+		m.cursor = slices.Index(m.graph.repos, target)
+		m.pane = reposPane
+		m.edge = 0
+	case "r":
+		m = m.reload()
+	case "x":
+		err := exportDeps(m.graph, m.config.Prefix)
+		if err != nil {
+			logError("failed to export dependency graph, err=%v", err)
+			m.msg = fmt.Sprintf("export failed: %v", err)
+		} else {
+			logInfo("exported dependency graph to %s", depsFile)
+			m.msg = fmt.Sprintf("exported to %s", depsFile)
+		}
+	}
+	return m, nil
+}
+
+func (m DepsModel) View() string {
+	if len(m.graph.repos) == 0 {
+		return "No repos selected.\n" + helpStyle.Render("\nesc: back • q: exit\n")
+	}
+
+	s := "Dependencies between the selected repos\n\n"
+	for i, name := range m.graph.repos {
+		line := strings.TrimPrefix(name, m.config.Prefix)
+		line += fmt.Sprintf("  (uses %d, used by %d)", len(m.graph.dependsOn(name)), len(m.graph.dependents(name)))
+		if err, ok := m.graph.errs[name]; ok {
+			line += "  ❗ " + err.Error()
+		}
+		if i == m.cursor {
+			if m.pane == reposPane {
+				line = selectedStyle.Render("> " + line)
+			} else {
+				line = "> " + line
+			}
+		} else {
+			line = "  " + line
+		}
+		s += line + "\n"
+	}
+
+	name := m.current()
+	s += m.viewEdges(usesPane, fmt.Sprintf("\n%s depends on:\n", strings.TrimPrefix(name, m.config.Prefix)), m.graph.dependsOn(name))
+	s += m.viewEdges(usedByPane, fmt.Sprintf("\n%s is used by:\n", strings.TrimPrefix(name, m.config.Prefix)), m.graph.dependents(name))
+
+	if m.msg != "" {
+		s += "\n" + m.msg + "\n"
+	}
+	s += helpStyle.Render("\njk: move • tab: switch list • enter: go to repo • x: export dot • r: reload • esc: back • q: exit\n")
+	return s
+}
+
+func (m DepsModel) viewEdges(pane depsPane, title string, edges []depEdge) string {
+	s := title
+	if len(edges) == 0 {
+		return s + helpStyle.Render("  none") + "\n"
+	}
+	for i, edge := range edges {
+		other := edge.to
+		if pane == usedByPane {
+			other = edge.from
+		}
+		line := fmt.Sprintf("%s %s", strings.TrimPrefix(other, m.config.Prefix), edge.version)
+		if edge.managed {
+			line += " (managed)"
+		} else if edge.parent {
+			line += " (parent)"
+		}
+		if m.pane == pane && i == m.edge {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		s += line + "\n"
+	}
+	return s
+}
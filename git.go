@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -30,7 +31,7 @@ func findGitRepos(parentDir string) ([]string, error) {
 func checkGitBranch(repoPath string, branch string) (bool, error) {
 	cmd := exec.Command("git", "rev-parse", "--verify", branch)
 	cmd.Dir = repoPath
-	out, err := cmd.Output()
+	out, err := runCmd(cmd)
 	if err != nil {
 		return false, err
 	}
@@ -44,7 +45,7 @@ func checkGitBranch(repoPath string, branch string) (bool, error) {
 func switchGitBranch(repoPath string, branch string) (bool, error) {
 	cmd := exec.Command("git", "switch", branch)
 	cmd.Dir = repoPath
-	_, err := cmd.Output()
+	_, err := runCmd(cmd)
 	if err != nil {
 		return false, err
 	}
@@ -55,51 +56,124 @@ func switchGitBranch(repoPath string, branch string) (bool, error) {
 func createGitBranch(repoPath string, branch string) (bool, error) {
 	cmd := exec.Command("git", "switch", "-c", branch)
 	cmd.Dir = repoPath
-	_, err := cmd.Output()
+	_, err := runCmd(cmd)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
+// run git symbolic-ref --short refs/remotes/origin/HEAD, falling back to
+// the current branch for repos without a remote HEAD
+func gitDefaultBranch(repoPath string) (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = repoPath
+	out, err := runCmd(cmd)
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/"), nil
+	}
+	branch, err := gitBranch(repoPath)
+	return strings.TrimSpace(branch), err
+}
+
 // run git rev-parse --abbrev-ref HEAD
 func gitBranch(repoPath string) (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = repoPath
-	out, err := cmd.Output()
+	out, err := runCmd(cmd)
 	if err != nil {
 		return "", err
 	}
 	return string(out), nil
 }
 
-// run git status -s
-func gitStatus(repoPath string) (string, error) {
-	cmd := exec.Command("git", "status", "-s")
+// run git add <file>
+func gitAdd(repoPath string, fileNames ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"add", "--"}, fileNames...)...)
 	cmd.Dir = repoPath
-	out, err := cmd.Output()
+	out, err := runCmd(cmd)
 	if err != nil {
 		return "", err
 	}
 	return string(out), nil
 }
 
-// run git add <file>
-func gitAdd(repoPath string, fileName string) (string, error) {
-	cmd := exec.Command("git", "add", fileName)
+// run git push -u origin HEAD
+func gitPush(repoPath string) (string, error) {
+	cmd := exec.Command("git", "push", "-u", "origin", "HEAD")
+	cmd.Dir = repoPath
+	out, err := runCmd(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// run git push origin refs/tags/<tag>
+func gitPushTag(repoPath string, tag string) (string, error) {
+	cmd := exec.Command("git", "push", "origin", "refs/tags/"+tag)
+	cmd.Dir = repoPath
+	out, err := runCmd(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// run git tag -a <tag> -m <msg>
+func gitTag(repoPath string, tag string, msg string) (string, error) {
+	cmd := exec.Command("git", "tag", "-a", tag, "-m", msg)
 	cmd.Dir = repoPath
-	out, err := cmd.Output()
+	out, err := runCmd(cmd)
 	if err != nil {
 		return "", err
 	}
 	return string(out), nil
 }
 
+// run git rev-parse -q --verify refs/tags/<tag>, a missing tag exits 1
+// without output
+func gitHasTag(repoPath string, tag string) (bool, error) {
+	cmd := exec.Command("git", "rev-parse", "-q", "--verify", "refs/tags/"+tag)
+	cmd.Dir = repoPath
+	_, err := runCmd(cmd)
+	var cmdErr *cmdError
+	if errors.As(err, &cmdErr) && strings.TrimSpace(cmdErr.stderr) == "" {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// run git switch --detach <tag>
+func gitCheckoutDetached(repoPath string, tag string) (string, error) {
+	cmd := exec.Command("git", "switch", "--detach", "refs/tags/"+tag)
+	cmd.Dir = repoPath
+	out, err := runCmd(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// run git status --porcelain -- <files>, true when any of them has staged
+// or unstaged changes
+func gitChanged(repoPath string, fileNames ...string) (bool, error) {
+	cmd := exec.Command("git", append([]string{"status", "--porcelain", "--"}, fileNames...)...)
+	cmd.Dir = repoPath
+	out, err := runCmd(cmd)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
 // run git commit -m <commit msg>
 func gitCommit(repoPath string, commitMsg string) (string, error) {
 	cmd := exec.Command("git", "commit", "-m", commitMsg)
 	cmd.Dir = repoPath
-	out, err := cmd.Output()
+	out, err := runCmd(cmd)
 	if err != nil {
 		return "", err
 	}
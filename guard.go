@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// values of Config.ReleaseGuard, empty means guardBlock
+const (
+	guardBlock = "block"
+	guardWarn  = "warn"
+	guardOff   = "off"
+)
+
+var guardModes = []string{guardBlock, guardWarn, guardOff}
+
+var errVersionReleased = errors.New("version was already released")
+
+func (c Config) releaseGuard() string {
+	if c.ReleaseGuard == "" {
+		return guardBlock
+	}
+	return c.ReleaseGuard
+}
+
+// ~/.m2/repository
+func localRepository() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".m2", "repository"), nil
+}
+
+// looks for an existing release of version in repo: its release tag (and
+// the plain v<version> and <version> spellings) and, if enabled, the local
+// maven repository. Returns where it was found or "" if it wasn't.
+func findRelease(config *Config, repo Repo, version string) (string, error) {
+	repoPath := fmt.Sprintf("./%s", repo.Name)
+	tags := []string{tagName(config, repo, version), "v" + version, version}
+	for i, tag := range tags {
+		if i > 0 && tag == tags[0] {
+			continue
+		}
+		has, err := gitHasTag(repoPath, tag)
+		if err != nil {
+			return "", err
+		}
+		if has {
+			return fmt.Sprintf("tag %s", tag), nil
+		}
+	}
+
+	if !config.CheckLocalRepo {
+		return "", nil
+	}
+	pom, err := readRepoPom(repoPath)
+	if err != nil {
+		return "", err
+	}
+	groupId, artifactId := pom.groupId(), pom.ArtifactId
+	m2, err := localRepository()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(m2, filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), artifactId, version)
+	if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%s-%s.pom", artifactId, version))); err == nil {
+		return dir, nil
+	}
+	return "", nil
+}
+
+// applies the release guard before repo is set to version. A non nil error
+// means the version must not be applied, in warn mode the reason is
+// returned as a warning for the repo's LastError instead. A release that
+// can't be looked up blocks like a found one.
+func guardRelease(config *Config, repo Repo, version string) (string, error) {
+	if config.releaseGuard() == guardOff {
+		return "", nil
+	}
+	if v, err := parseVersion(version); err == nil && v.isSnapshot() {
+		return "", nil
+	}
+	found, err := findRelease(config, repo, version)
+	if err != nil {
+		err = fmt.Errorf("failed to check for an existing release of %s %s: %w", repo.Name, version, err)
+	} else if found != "" {
+		err = fmt.Errorf("%w: %s %s, found %s", errVersionReleased, repo.Name, version, found)
+	}
+	if err != nil && config.releaseGuard() == guardWarn {
+		logWarn("%v", err)
+		return err.Error(), nil
+	}
+	return "", err
+}
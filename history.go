@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	historyFile = ".massgit/history.jsonl"
+	// entries shown at once in the history view
+	historyPage = 15
+)
+
+var historyMu sync.Mutex
+
+type RepoOutcome struct {
+	Name    string `json:"name"`
+	Ok      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Elapsed int64  `json:"elapsedMs"`
+}
+
+type HistoryEntry struct {
+	Time    time.Time     `json:"time"`
+	Op      string        `json:"op"`
+	Repos   []RepoOutcome `json:"repos"`
+	Elapsed int64         `json:"elapsedMs"`
+}
+
+func (e HistoryEntry) failures() int {
+	failed := 0
+	for _, repo := range e.Repos {
+		if !repo.Ok {
+			failed++
+		}
+	}
+	return failed
+}
+
+// builds the outcome of an operation on repo from its last error
+func newOutcome(repo *Repo, start time.Time) RepoOutcome {
+	return RepoOutcome{
+		Name:    repo.Name,
+		Ok:      repo.LastError == "",
+		Error:   repo.LastError,
+		Elapsed: time.Since(start).Milliseconds(),
+	}
+}
+
+// appends an operation to the history file, outcomes with no name are
+// repos that were skipped and are left out
+func recordHistory(op string, start time.Time, outcomes []RepoOutcome) {
+	entry := HistoryEntry{
+		Time:    start,
+		Op:      op,
+		Repos:   make([]RepoOutcome, 0, len(outcomes)),
+		Elapsed: time.Since(start).Milliseconds(),
+	}
+	for _, outcome := range outcomes {
+		if outcome.Name != "" {
+			entry.Repos = append(entry.Repos, outcome)
+		}
+	}
+
+	err := appendHistory(entry)
+	if err != nil {
+		logError("failed to record %s in history, err=%v", op, err)
+	}
+}
+
+func appendHistory(entry HistoryEntry) error {
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	file, err := os.OpenFile(historyFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(bytes, '\n'))
+	return err
+}
+
+// reads the history file, newest entry first
+func loadHistory() ([]HistoryEntry, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	file, err := os.Open(historyFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			logWarn("skipping unreadable history line, err=%v", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, scanner.Err()
+}
+
+type HistoryModel struct {
+	config   *Config
+	entries  []HistoryEntry
+	cursor   int
+	expanded bool
+	err      error
+}
+
+func NewHistory(config *Config) HistoryModel {
+	return HistoryModel{
+		config: config,
+	}
+}
+
+// rereads the history file, called each time the view is opened
+func (m HistoryModel) reload() HistoryModel {
+	m.entries, m.err = loadHistory()
+	m.cursor = 0
+	m.expanded = false
+	return m
+}
+
+func (m HistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			m.expanded = !m.expanded
+		case "esc", "H":
+			m.config.state = homeView
+			return m, tea.ClearScreen
+		}
+	case errMsg:
+		m.err = msg
+	}
+	return m, nil
+}
+
+func (m HistoryModel) View() string {
+	var s string = "History\n\n"
+	if m.err != nil {
+		s += fmt.Sprintf("failed to read history: %v\n", m.err)
+	}
+	if len(m.entries) == 0 {
+		s += "no operations recorded yet\n"
+	}
+
+	start := 0
+	if m.cursor >= historyPage {
+		start = m.cursor - historyPage + 1
+	}
+	end := min(start+historyPage, len(m.entries))
+	for i := start; i < end; i++ {
+		entry := m.entries[i]
+		cursor := " "
+		if i == m.cursor {
+			cursor = selectedStyle.Render(">")
+		}
+		outcome := "🟢"
+		if entry.failures() > 0 {
+			outcome = fmt.Sprintf("🔴 %d failed", entry.failures())
+		}
+		s += fmt.Sprintf("%s %s  %-7s %3d repos %6dms  %s\n", cursor, entry.Time.Format(time.DateTime),
+			entry.Op, len(entry.Repos), entry.Elapsed, outcome)
+
+		if i == m.cursor && m.expanded {
+			for _, repo := range entry.Repos {
+				status := "ok"
+				if !repo.Ok {
+					status = "failed: " + strings.ReplaceAll(repo.Error, "\n", " ")
+				}
+				s += fmt.Sprintf("      %-30s %6dms  %s\n", repo.Name, repo.Elapsed, status)
+			}
+		}
+	}
+
+	s += helpStyle.Render("\njk mvmt • enter: details • esc: back • q: exit\n")
+	return s
+}
@@ -1,18 +1,24 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 type HomeModel struct {
-	config  *Config
-	current int
-	err     error
+	config     *Config
+	current    int
+	showError  bool
+	refreshing bool
+	watcher    *repoWatcher
+	err        error
 }
 
 func NewHome(config *Config) HomeModel {
@@ -29,10 +35,59 @@ func (m HomeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case refreshTickMsg:
+		if msg.periodic && msg.gen != m.config.refreshTick {
+			return m, nil
+		}
+		if msg.periodic {
+			cmd = nextRefreshTick(m.config)
+		}
+		if m.refreshing || len(m.config.VisibleRepos) == 0 {
+			return m, cmd
+		}
+		m.refreshing = true
+		for i := range m.config.Repos {
+			m.config.Repos[i].stale = m.config.Repos[i].Selected
+		}
+		return m, tea.Batch(cmd, refreshRepos(m.config.Repos, m.config.refreshGen))
+	case refreshMsg:
+		if !msg.partial {
+			m.refreshing = false
+		}
+		mergeRefresh(m.config, msg)
+		if m.watcher != nil && !msg.partial {
+			m.watcher.sync(m.config.Repos)
+		}
+		return m, nil
+	case watchMsg:
+		if m.watcher == nil {
+			return m, nil
+		}
+		cmds := []tea.Cmd{m.watcher.wait()}
+		idx := slices.IndexFunc(m.config.Repos, func(r Repo) bool {
+			return r.Name == msg.name
+		})
+		if idx >= 0 && m.config.Repos[idx].Selected {
+			cmds = append(cmds, statusRepo(m.config.Repos[idx], m.config.refreshGen))
+		}
+		return m, tea.Batch(cmds...)
 	case tea.KeyMsg:
+		if m.showError {
+			switch msg.String() {
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			case "esc", "e", "enter":
+				m.showError = false
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "e":
+			if m.current < len(m.config.Repos) && m.config.Repos[m.current].LastError != "" {
+				m.showError = true
+			}
 		case "up", "k":
 			idx := m.current - m.config.Cols
 			if !(len(m.config.VisibleRepos)%2 == 0) {
@@ -61,34 +116,74 @@ func (m HomeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "s":
 			m.config.state = settingsView
 			return m, tea.ClearScreen
+		case "H":
+			m.config.state = historyView
+			return m, tea.ClearScreen
+		case "t":
+			m.config.state = timingView
+			return m, tea.ClearScreen
+		case "R":
+			m.config.state = releaseView
+			return m, tea.ClearScreen
+		case "T":
+			m.config.state = tagView
+			return m, tea.ClearScreen
+		case "D":
+			m.config.state = depsView
+			return m, tea.ClearScreen
+		case "B":
+			m.config.state = buildView
+			return m, tea.ClearScreen
+		case "w":
+			m.config.Watch = !m.config.Watch
+			return m.setWatching(m.config.Watch)
 		case "c":
 			var wg sync.WaitGroup
+			start := time.Now()
+			outcomes := make([]RepoOutcome, len(m.config.Repos))
 			for i := range m.config.Repos {
 				wg.Add(1)
 				var repoPath = fmt.Sprintf("./%s", m.config.Repos[i].Name)
 				go func() {
 					defer wg.Done()
-					_, err := gitAdd(repoPath, "pom.xml")
+					repoStart := time.Now()
+					var errs []error
+					// repos without version changes have nothing to commit,
+					// they may still be dependents a save propagated to
+					files := versionFiles(repoPath)
+					changed, err := gitChanged(repoPath, files...)
+					if err == nil && !changed {
+						logDebug("no version changes in %s, skipping commit", m.config.Repos[i].Name)
+						return
+					}
+					_, err = gitAdd(repoPath, files...)
 
 					if err != nil {
-						fmt.Println("failed to add pom.xml")
+						errs = append(errs, err)
+						logError("failed to add pom.xml for %s, err=%v", m.config.Repos[i].Name, err)
 					}
 
 					_, err = gitCommit(repoPath, "update pom version")
 
 					if err != nil {
-						fmt.Println("failed to commit pom.xml")
+						errs = append(errs, err)
+						logError("failed to commit pom.xml for %s, err=%v", m.config.Repos[i].Name, err)
 					}
 
-					status, err := gitStatus(repoPath)
+					status, err := gitStatusV2(repoPath)
 					if err != nil {
-						fmt.Printf("failed to get status for %s, err=%v\n", m.config.Repos[i].Name, err)
+						errs = append(errs, err)
+						logError("failed to get status for %s, err=%v", m.config.Repos[i].Name, err)
 					} else {
-						m.config.Repos[i].Modified = !(status == "")
+						m.config.Repos[i].setStatus(status)
 					}
+					m.config.Repos[i].setError(errors.Join(errs...))
+					outcomes[i] = newOutcome(&m.config.Repos[i], repoStart)
 				}()
 			}
 			wg.Wait()
+			m.config.refreshGen++
+			recordHistory("commit", start, outcomes)
 		}
 
 	case errMsg:
@@ -101,6 +196,13 @@ func (m HomeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m HomeModel) View() string {
 	var s string = ""
+	if m.showError {
+		repo := m.config.Repos[m.current]
+		s += fmt.Sprintf("Last error for %s:\n\n", repo.Name)
+		s += errorStyle.Render(repo.LastError)
+		s += helpStyle.Render("\nesc: back • q: exit\n")
+		return s
+	}
 	// if m.config.clearPending {
 	// 	s += "\033[0J"
 	// 	m.config.clearPending = false
@@ -117,10 +219,24 @@ func (m HomeModel) View() string {
 			continue
 		}
 		trimmedRepo := strings.TrimPrefix(repo.Name, m.config.Prefix)
+		if repo.LastError != "" {
+			trimmedRepo = "❗" + trimmedRepo
+		}
+		if repo.stale {
+			trimmedRepo = "↻ " + trimmedRepo
+		}
+		if repo.isParent {
+			trimmedRepo = "⌂ " + trimmedRepo
+		}
+		parentVersion := repo.Maven.ParentVersion
+		if repo.parentMismatch {
+			parentVersion = conflictStyle.Render(parentVersion + " ⚠")
+		}
+		version := repo.Maven.Version + moduleLabel(repo)
 		if i == m.current {
-			sub = append(sub, focusedModelStyle.Render(fmt.Sprintf("%s\n%s %s\nv: %s\npv: %s", trimmedRepo, getModifiedColor(repo.Modified), repo.Branch, repo.Maven.Version, repo.Maven.ParentVersion)))
+			sub = append(sub, focusedModelStyle.Render(fmt.Sprintf("%s\n%s %s\n%s\nv: %s\npv: %s", trimmedRepo, getStatusIcon(repo.Status), branchLabel(repo), summarizeStatus(repo.Status), version, parentVersion)))
 		} else {
-			sub = append(sub, modelStyle.Render(fmt.Sprintf("%s\n%s %s\nv: %s\npv: %s", trimmedRepo, getModifiedColor(repo.Modified), repo.Branch, repo.Maven.Version, repo.Maven.ParentVersion)))
+			sub = append(sub, modelStyle.Render(fmt.Sprintf("%s\n%s %s\n%s\nv: %s\npv: %s", trimmedRepo, getStatusIcon(repo.Status), branchLabel(repo), summarizeStatus(repo.Status), version, parentVersion)))
 		}
 	}
 	numCols := m.config.Cols
@@ -141,16 +257,95 @@ func (m HomeModel) View() string {
 
 	s += lipgloss.JoinVertical(lipgloss.Top, sub2...)
 
-	s += helpStyle.Render("\nhjkl mvmt • s: settings • c: commit changes • q: exit\n")
+	if m.watcher != nil {
+		s += helpStyle.Render("\nwatching for changes")
+	}
+	s += helpStyle.Render("\nhjkl mvmt • s: settings • c: commit changes • R: release • T: tags • D: deps • B: build • H: history • t: timings • w: watch • e: show error • ctrl+l: logs • q: exit\n")
 
 	// style := lipgloss.NewStyle().Height(20).Width(80).Padding(1, 2)
 	return s
 }
 
-func getModifiedColor(modified bool) string {
-	if modified {
+// the first refresh, the refresh interval and the watcher, started once
+// the workspace is set up
+func (m HomeModel) start() tea.Cmd {
+	cmds := []tea.Cmd{
+		func() tea.Msg { return refreshTickMsg{} },
+		nextRefreshTick(m.config),
+	}
+	if m.watcher != nil {
+		cmds = append(cmds, m.watcher.wait())
+	}
+	return tea.Batch(cmds...)
+}
+
+// starts or stops watching the selected repos for changes
+func (m HomeModel) setWatching(on bool) (HomeModel, tea.Cmd) {
+	if !on {
+		if m.watcher != nil {
+			m.watcher.Close()
+			m.watcher = nil
+			logInfo("stopped watching repos")
+		}
+		return m, nil
+	}
+	if m.watcher != nil {
+		return m, nil
+	}
+	watcher, err := newRepoWatcher()
+	if err != nil {
+		logError("failed to start watching repos, err=%v", err)
+		m.config.Watch = false
+		return m, nil
+	}
+	watcher.sync(m.config.Repos)
+	m.watcher = watcher
+	logInfo("watching %d repos", len(m.config.VisibleRepos))
+	return m, watcher.wait()
+}
+
+// the most pressing state of the repo: conflicts, then an unfinished
+// merge/rebase/cherry-pick, then local changes, then detached HEAD
+func getStatusIcon(status GitStatus) string {
+	switch {
+	case status.Conflicted > 0:
+		return "🔴"
+	case status.Operation != "":
+		return "🟠"
+	case !status.clean():
 		return "🟡"
-	} else {
+	case status.Detached:
+		return "⚪"
+	default:
 		return "🟢"
 	}
 }
+
+// the module count of a multi-module repo, flagged when some modules are
+// not at the repo's version
+func moduleLabel(repo Repo) string {
+	if repo.Maven.Modules == 0 {
+		return ""
+	}
+	if len(repo.Maven.InconsistentModules) > 0 {
+		return conflictStyle.Render(fmt.Sprintf(" ▦%d⚠%d", repo.Maven.Modules, len(repo.Maven.InconsistentModules)))
+	}
+	return helpStyle.Render(fmt.Sprintf(" ▦%d", repo.Maven.Modules))
+}
+
+func branchLabel(repo Repo) string {
+	label := repo.Branch
+	if repo.Status.Detached {
+		label = "detached"
+	}
+	if repo.Status.Operation != "" {
+		label = fmt.Sprintf("%s (%s)", label, repo.Status.Operation)
+	}
+	if repo.Status.Ahead > 0 {
+		label += fmt.Sprintf(" ↑%d", repo.Status.Ahead)
+	}
+	if repo.Status.Behind > 0 {
+		label += fmt.Sprintf(" ↓%d", repo.Status.Behind)
+	}
+	return label
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type logLevel uint
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+const (
+	logDir      = ".massgit/logs"
+	logName     = "massgit.log"
+	maxLogSize  = 1 << 20
+	maxLogFiles = 5
+	logBacklog  = 200
+	// lines shown in the in-app log panel
+	logPanelLines = 8
+)
+
+func (l logLevel) String() string {
+	switch l {
+	case levelDebug:
+		return "DEBUG"
+	case levelInfo:
+		return "INFO"
+	case levelWarn:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// Logger writes leveled lines to .massgit/logs and keeps the most recent
+// ones in memory for the in-app log panel, since bubbletea owns stdout
+type Logger struct {
+	mu     sync.Mutex
+	level  logLevel
+	dir    string
+	file   *os.File
+	size   int64
+	recent []string
+}
+
+var logger = &Logger{level: levelInfo}
+
+// opens the log file under dir, debug lowers the level to include debug lines
+func (l *Logger) open(dir string, debug bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if debug {
+		l.level = levelDebug
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	l.dir = dir
+	return l.openFile()
+}
+
+func (l *Logger) openFile() error {
+	file, err := os.OpenFile(filepath.Join(l.dir, logName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// shifts massgit.log -> massgit.log.1 -> ... dropping the oldest
+func (l *Logger) rotate() {
+	l.file.Close()
+	l.file = nil
+	base := filepath.Join(l.dir, logName)
+	os.Remove(fmt.Sprintf("%s.%d", base, maxLogFiles))
+	for i := maxLogFiles - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", base, i), fmt.Sprintf("%s.%d", base, i+1))
+	}
+	os.Rename(base, base+".1")
+	err := l.openFile()
+	if err != nil {
+		l.remember(fmt.Sprintf("%s ERROR failed to reopen log, err=%v", time.Now().Format(time.DateTime), err))
+	}
+}
+
+func (l *Logger) remember(line string) {
+	l.recent = append(l.recent, line)
+	if len(l.recent) > logBacklog {
+		l.recent = l.recent[len(l.recent)-logBacklog:]
+	}
+}
+
+func (l *Logger) log(level logLevel, format string, args ...any) {
+	if level < l.level {
+		return
+	}
+	msg := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	line := fmt.Sprintf("%s %-5s %s", time.Now().Format(time.DateTime), level, msg)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.remember(line)
+	if l.file == nil {
+		return
+	}
+	n, _ := fmt.Fprintln(l.file, line)
+	l.size += int64(n)
+	if l.size > maxLogSize {
+		l.rotate()
+	}
+}
+
+// returns up to n of the most recent log lines, oldest first
+func (l *Logger) Recent(n int) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n > len(l.recent) {
+		n = len(l.recent)
+	}
+	return append([]string(nil), l.recent[len(l.recent)-n:]...)
+}
+
+func (l *Logger) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+func logDebug(format string, args ...any) {
+	logger.log(levelDebug, format, args...)
+}
+
+func logInfo(format string, args ...any) {
+	logger.log(levelInfo, format, args...)
+}
+
+func logWarn(format string, args ...any) {
+	logger.log(levelWarn, format, args...)
+}
+
+func logError(format string, args ...any) {
+	logger.log(levelError, format, args...)
+}
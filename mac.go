@@ -8,11 +8,11 @@ func init() {
 	shell = "zsh"
 	grep = "grep"
 	sed = "sed"
-	updateMvn = func(version string, lineNum string) []string {
+	updateMvn = func(tag string, version string, lineNum string) []string {
 		return []string{
 			"-i",
 			"",
-			fmt.Sprintf("%ss#^\\([ \\t]*\\).*#\\1<version>%s</version>#", lineNum, version),
+			fmt.Sprintf("%ss#^\\([ \\t]*\\).*#\\1<%s>%s</%s>#", lineNum, tag, version, tag),
 			"pom.xml",
 		}
 	}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -17,13 +17,20 @@ const (
 	defaultTime              = time.Minute
 	homeView    sessionState = iota
 	settingsView
+	historyView
+	wizardView
+	timingView
+	releaseView
+	tagView
+	depsView
+	buildView
 )
 
 var (
 	shell      string
 	grep       string
 	sed        string
-	updateMvn  func(string, string) []string
+	updateMvn  func(string, string, string) []string
 	modelStyle = lipgloss.NewStyle().
 			Width(15).
 			Height(5).
@@ -42,8 +49,22 @@ var (
 			Align(lipgloss.Center, lipgloss.Center).
 			BorderStyle(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("78"))
+	errorStyle = lipgloss.NewStyle().
+			Width(80).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("9")).
+			Padding(0, 1)
 	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
 	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	stagedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	unstagedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	conflictStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	logStyle      = lipgloss.NewStyle().
+			Width(116).
+			Foreground(lipgloss.Color("245")).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderTop(true).
+			BorderForeground(lipgloss.Color("8"))
 
 	mainStyle = lipgloss.NewStyle().Height(20).Width(120).Padding(1, 1)
 )
@@ -62,61 +83,150 @@ type Maven struct {
 	Vln           string `json:"versionLn"`
 	ParentVersion string `json:"parentVersion"`
 	Pvln          string `json:"parentVersionLn"`
+	// set when the version is ${property}, the line numbers are then those
+	// of the property's definition, empty when .mvn/maven.config defines it
+	VersionProperty       string `json:"versionProperty,omitempty"`
+	ParentVersionProperty string `json:"parentVersionProperty,omitempty"`
+	// set when the version is built from several properties such as
+	// ${revision}${changelist}, the version is then its expansion and is
+	// left for the user to change
+	VersionExpr       string `json:"versionExpr,omitempty"`
+	ParentVersionExpr string `json:"parentVersionExpr,omitempty"`
+	// the modules of a multi-module repo and those not at Version
+	Modules             int      `json:"modules,omitempty"`
+	InconsistentModules []string `json:"inconsistentModules,omitempty"`
 }
 
 type Repo struct {
-	Name     string `json:"name"`
-	Branch   string `json:"branch"`
-	Modified bool   `json:"modified"`
-	Selected bool   `json:"selected"`
-	Maven    Maven  `json:"maven"`
-}
-
-type model struct {
-	config   Config
-	settings SettingsModel
-	home     HomeModel
+	Name      string    `json:"name"`
+	Branch    string    `json:"branch"`
+	Selected  bool      `json:"selected"`
+	Maven     Maven     `json:"maven"`
+	Status    GitStatus `json:"status"`
+	LastError string    `json:"lastError,omitempty"`
+	// detected when the workspace is set up
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	BuildType     string `json:"buildType,omitempty"`
+	// set while a background refresh of the repo is running
+	stale bool
+	// the workspace repo that is this repo's <parent>, see linkParents
+	parent         string
+	isParent       bool
+	parentMismatch bool
+	// version the next save sets instead of Config.Version, see bumpPlan
+	targetVersion string
 }
 
-type Config struct {
-	Repos         []Repo `json:"repos"`
-	VisibleRepos  []int  `json:"visibleRepos"`
-	Branch        string `json:"branch"`
-	Version       string `json:"version"`
-	ParentVersion string `json:"parentVersion"`
-	Prefix        string `json:"prefix"`
-	Cols          int    `json:"cols"`
-	state         sessionState
+func (r *Repo) setStatus(status GitStatus) {
+	r.Status = status
+	r.Branch = status.Branch
 }
 
-func (c Config) String() string {
-	bytes, err := json.Marshal(c)
-	if err != nil {
-		return "failed"
+// records the outcome of the last operation on the repo, nil clears it
+func (r *Repo) setError(err error) {
+	if err == nil {
+		r.LastError = ""
+		return
 	}
-	return string(bytes)
+	r.LastError = err.Error()
 }
 
-func (c Config) save() error {
-	fmt.Println(c)
-	err := saveConfig(c)
-	if err != nil {
-		fmt.Println("failed to save config", err)
-	}
-	return err
+type model struct {
+	config   Config
+	settings SettingsModel
+	home     HomeModel
+	history  HistoryModel
+	wizard   WizardModel
+	timing   TimingModel
+	release  ReleaseModel
+	tag      TagModel
+	deps     DepsModel
+	build    BuildModel
+	showLog  bool
+	keys     map[string]string
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	if m.config.state == wizardView {
+		return m.wizard.Init()
+	}
+	return m.home.start()
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 	var updatedModel tea.Model
+	if m.config.state == wizardView {
+		updatedModel, cmd = m.wizard.Update(msg)
+		m.wizard = updatedModel.(WizardModel)
+		m.config.state = m.wizard.config.state
+		if m.config.state != wizardView {
+			// the grid's background work waited for the workspace
+			if m.home.config.Watch {
+				m.home, _ = m.home.setWatching(true)
+			}
+			cmd = tea.Batch(cmd, m.home.start())
+		}
+		return m, cmd
+	}
 	switch msg := msg.(type) {
+	case buildMsg:
+		updatedModel, cmd = m.build.Update(msg)
+		m.build = updatedModel.(BuildModel)
+		cmds = append(cmds, cmd)
+	case releaseStepMsg:
+		updatedModel, cmd = m.release.Update(msg)
+		m.release = updatedModel.(ReleaseModel)
+		cmds = append(cmds, cmd)
+	case saveMsg:
+		updatedModel, cmd = m.settings.Update(msg)
+		m.settings = updatedModel.(SettingsModel)
+		m.config.state = m.settings.config.state
+		cmds = append(cmds, cmd)
+	case refreshTickMsg, refreshMsg, watchMsg:
+		updatedModel, cmd = m.home.Update(msg)
+		m.home = updatedModel.(HomeModel)
+		cmds = append(cmds, cmd)
 	case tea.KeyMsg:
+		if !m.editing() {
+			msg = remapKey(m.keys, msg)
+		}
+		if msg.String() == "ctrl+l" {
+			m.showLog = !m.showLog
+			return m, tea.ClearScreen
+		}
 		switch m.config.state {
+		case releaseView:
+			updatedModel, cmd = m.release.Update(msg)
+			m.release = updatedModel.(ReleaseModel)
+			m.config.state = m.release.config.state
+			cmds = append(cmds, cmd)
+		case tagView:
+			updatedModel, cmd = m.tag.Update(msg)
+			m.tag = updatedModel.(TagModel)
+			m.config.state = m.tag.config.state
+			cmds = append(cmds, cmd)
+		case buildView:
+			updatedModel, cmd = m.build.Update(msg)
+			m.build = updatedModel.(BuildModel)
+			m.config.state = m.build.config.state
+			cmds = append(cmds, cmd)
+		case depsView:
+			updatedModel, cmd = m.deps.Update(msg)
+			m.deps = updatedModel.(DepsModel)
+			m.config.state = m.deps.config.state
+			cmds = append(cmds, cmd)
+		case timingView:
+			updatedModel, cmd = m.timing.Update(msg)
+			m.timing = updatedModel.(TimingModel)
+			m.config.state = m.timing.config.state
+			cmds = append(cmds, cmd)
+		case historyView:
+			updatedModel, cmd = m.history.Update(msg)
+			m.history = updatedModel.(HistoryModel)
+			m.config.state = m.history.config.state
+			cmds = append(cmds, cmd)
 		case settingsView:
 			// cmds = append(cmds, m.settings.branch.Focus())
 			updatedModel, cmd = m.settings.Update(msg)
@@ -127,6 +237,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			updatedModel, cmd = m.home.Update(msg)
 			m.home = updatedModel.(HomeModel)
 			m.config.state = m.home.config.state
+			if m.config.state == historyView {
+				m.history = m.history.reload()
+			} else if m.config.state == timingView {
+				m.timing = m.timing.reset()
+			} else if m.config.state == releaseView {
+				m.release = m.release.reset()
+			} else if m.config.state == tagView {
+				m.tag = m.tag.reset()
+			} else if m.config.state == depsView {
+				m.deps = m.deps.reload()
+			} else if m.config.state == buildView {
+				m.build = m.build.reset()
+			}
 			cmds = append(cmds, cmd)
 		}
 	}
@@ -134,114 +257,121 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// true while a text input has focus and keys should reach it untranslated
+func (m model) editing() bool {
+	switch m.config.state {
+	case settingsView:
+		return m.settings.editing()
+	case tagView:
+		return m.tag.state == tagInput
+	}
+	return false
+}
+
 func (m model) View() string {
 	var s string
-	if m.config.state == settingsView {
+	switch m.config.state {
+	case wizardView:
+		s += m.wizard.View()
+	case settingsView:
 		s += m.settings.View()
-	} else {
+	case historyView:
+		s += m.history.View()
+	case timingView:
+		s += m.timing.View()
+	case releaseView:
+		s += m.release.View()
+	case tagView:
+		s += m.tag.View()
+	case depsView:
+		s += m.deps.View()
+	case buildView:
+		s += m.build.View()
+	default:
 		s += m.home.View()
 	}
+	if m.showLog {
+		s += "\n" + logStyle.Render(strings.Join(logger.Recent(logPanelLines), "\n"))
+	}
 	return mainStyle.Render(s)
 	// return s
 }
 
-func summarizeStatus(status string) string {
-	if status == "" {
-		return "Clean"
-	}
-	// Or count lines, etc.
-	return fmt.Sprintf("%d changes", len(strings.Split(status, "\n"))-1)
-}
-
-func getConfig() (Config, bool) {
-	var config Config
-
-	_, err := os.ReadDir(".massgit")
-	if err != nil {
-		fmt.Println(".massgit does not exist", err)
-		return config, false
-	}
-
-	bytes, err := os.ReadFile(".massgit/config.json")
+func newModel(flags layeredSettings, profile string, watch bool) model {
+	config, ok := getConfig()
+	if !ok {
+		var err error
+		config, err = createConfig(config)
 
-	if err != nil {
-		fmt.Println("config does not exist", err)
-		return config, false
+		if err != nil {
+			logError("failed to create config, err=%v", err)
+			fmt.Printf("failed to create %s: %v\n", configDir, err)
+			os.Exit(1)
+		}
 	}
 
-	err = json.Unmarshal(bytes, &config)
-
-	if err != nil {
-		fmt.Println("failed to unmarshal config file", err)
-		return config, false
+	user := getUserConfig()
+	applyLayers(&config, user, flags)
+	applyTheme(user.Theme)
+	if profile != "" && profile != config.ActiveProfile {
+		err := config.switchProfile(profile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	}
 
-	return config, true
-}
-
-func createConfig(config Config) error {
-	_, err := os.Stat(".massgit")
-	if err == nil {
-		config.Repos = []Repo{}
-		config.Branch = "master"
-		config.Cols = 4
-		config.VisibleRepos = []int{}
-		return saveConfig(config)
+	if !ok {
+		config.state = wizardView
 	}
 
-	err = os.Mkdir(".massgit", 0755)
+	config.Watch = config.Watch || watch
 
-	if err != nil {
-		fmt.Println("failed to create massgit cache")
-		return err
+	m := model{config: config, keys: user.Keys}
+	m.settings = NewSettings(&config)
+	m.home = NewHome(&config)
+	if config.Watch && config.state != wizardView {
+		m.home, _ = m.home.setWatching(true)
 	}
-
-	config.Repos = []Repo{}
-	config.Branch = "master"
-	config.Cols = 4
-	config.VisibleRepos = []int{}
-	return saveConfig(config)
+	m.history = NewHistory(&config)
+	m.wizard = NewWizard(&config)
+	m.timing = NewTiming(&config)
+	m.release = NewRelease(&config)
+	m.tag = NewTag(&config)
+	m.deps = NewDeps(&config)
+	m.build = NewBuild(&config)
+	return m
 }
 
-func saveConfig(config Config) error {
-	bytes, err := json.Marshal(config)
-
+func main() {
+	debug := flag.Bool("debug", false, "write debug output to the log file")
+	workspace := flag.String("workspace", "", "workspace directory holding .massgit and the repos (default: nearest .massgit above the current directory)")
+	var flags layeredSettings
+	flag.StringVar(&flags.Branch, "branch", "", "branch to use, overrides config and MASSGIT_BRANCH")
+	flag.StringVar(&flags.Prefix, "prefix", "", "project name prefix to hide, overrides config and MASSGIT_PREFIX")
+	flag.IntVar(&flags.Cols, "cols", 0, "number of columns to display, overrides config and MASSGIT_COLS")
+	profile := flag.String("profile", "", "workspace profile to switch to")
+	watch := flag.Bool("watch", false, "watch the selected repos and update tiles as files change")
+	flag.Parse()
+
+	root, err := enterWorkspace(*workspace)
 	if err != nil {
-		fmt.Println("failed to marshal config")
-		return err
+		fmt.Printf("failed to open workspace: %v\n", err)
+		os.Exit(1)
 	}
 
-	err = os.WriteFile(".massgit/config.json", bytes, 0644)
-
+	err = logger.open(logDir, *debug)
 	if err != nil {
-		fmt.Println("failed to write config")
-		return err
-	}
-
-	return nil
-}
-
-func newModel() model {
-	config, ok := getConfig()
-	if !ok {
-		err := createConfig(config)
-
-		if err != nil {
-			fmt.Println("config in bad state, try deleting '.massgit'")
-			os.Exit(1)
-		}
+		fmt.Printf("failed to open log file: %v\n", err)
 	}
+	defer logger.Close()
+	logInfo("using workspace %s", root)
 
-	m := model{config: config}
-	m.settings = NewSettings(&config)
-	m.home = NewHome(&config)
-	return m
-}
-
-func main() {
-	p := tea.NewProgram(newModel(), tea.WithAltScreen())
+	p := tea.NewProgram(newModel(flags, *profile, *watch), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
+		logError("program exited with err=%v", err)
 		fmt.Printf("Alas, there's been an error: %v", err)
+		logger.Close()
 		os.Exit(1)
 	}
 }
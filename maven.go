@@ -2,10 +2,98 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+const mavenConfigFile = ".mvn/maven.config"
+
+var mavenConfigProperty = regexp.MustCompile(`^-D([^=\s]+)=(\S*)$`)
+
+// the -Dname=value user properties of .mvn/maven.config, which take
+// precedence over the pom's properties
+func readMavenConfig(repoPath string) map[string]string {
+	props := map[string]string{}
+	bytes, err := os.ReadFile(filepath.Join(repoPath, mavenConfigFile))
+	if err != nil {
+		return props
+	}
+	for _, field := range strings.Fields(string(bytes)) {
+		if match := mavenConfigProperty.FindStringSubmatch(field); match != nil {
+			props[match[1]] = match[2]
+		}
+	}
+	return props
+}
+
+func setMavenConfigProperty(repoPath string, name string, value string) error {
+	path := filepath.Join(repoPath, mavenConfigFile)
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	re := regexp.MustCompile(`(-D` + regexp.QuoteMeta(name) + `=)\S*`)
+	if !re.Match(bytes) {
+		return fmt.Errorf("%s not defined in %s", name, path)
+	}
+	updated := re.ReplaceAll(bytes, []byte("${1}"+value))
+	return writeFileAtomic(path, updated, 0644)
+}
+
+// finds the line of pom.xml defining the property in the project's own
+// <properties>, see projectProperties
+func propertyLine(repoPath string, name string) (string, string, bool) {
+	bytes, err := os.ReadFile(filepath.Join(repoPath, "pom.xml"))
+	if err != nil {
+		return "", "", false
+	}
+	content := string(bytes)
+	start, end, ok := projectProperties(content)
+	if !ok {
+		return "", "", false
+	}
+	re := regexp.MustCompile(`<` + regexp.QuoteMeta(name) + `>\s*([^<]*?)\s*</` + regexp.QuoteMeta(name) + `>`)
+	loc := re.FindStringSubmatchIndex(content[start:end])
+	if loc == nil {
+		return "", "", false
+	}
+	ln := strings.Count(content[:start+loc[0]], "\n") + 1
+	return content[start+loc[2] : start+loc[3]], strconv.Itoa(ln), true
+}
+
+// resolves a ${property} version through .mvn/maven.config and then the
+// pom's properties, returning the value, the property and the line of its
+// definition in pom.xml, empty when maven.config defines it
+func resolveVersion(repoPath string, raw string) (string, string, string, error) {
+	match := propertyRef.FindStringSubmatch(raw)
+	if match == nil && strings.Contains(raw, "${") {
+		// a composite version is only expanded for display
+		pom, err := readRepoPom(repoPath)
+		if err != nil {
+			return raw, "", "", err
+		}
+		if value := pom.expand(raw); !strings.Contains(value, "${") {
+			return value, "", "", nil
+		}
+		return raw, "", "", fmt.Errorf("version %s uses properties that are not defined", raw)
+	}
+	if match == nil {
+		return raw, "", "", nil
+	}
+	name := match[1]
+	if value, ok := readMavenConfig(repoPath)[name]; ok {
+		return value, name, "", nil
+	}
+	if value, ln, ok := propertyLine(repoPath, name); ok {
+		return value, name, ln, nil
+	}
+	return raw, "", "", fmt.Errorf("property %s is not defined in pom.xml or %s", name, mavenConfigFile)
+}
+
 func mvnVersion(repoPath string, repo *Repo) error {
 	var (
 		output []byte
@@ -18,10 +106,10 @@ func mvnVersion(repoPath string, repo *Repo) error {
 	)
 
 	cmd.Dir = repoPath
-	output, err = cmd.Output()
+	output, err = runCmd(cmd)
 
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		logError("failed to read mvn version for %s, err=%v", repo.Name, err)
 		return err
 	}
 	versions := strings.Split(string(output), "\n")
@@ -39,9 +127,63 @@ func mvnVersion(repoPath string, repo *Repo) error {
 			repo.Maven.Pvln = strings.TrimSpace(ln)
 		}
 	}
+
+	repo.Maven.VersionProperty = ""
+	repo.Maven.ParentVersionProperty = ""
+	repo.Maven.VersionExpr = ""
+	repo.Maven.ParentVersionExpr = ""
+	// a version that can't be resolved is shown as it is and can't be set
+	value, property, ln, err := resolveVersion(repoPath, repo.Maven.Version)
+	if err != nil {
+		logWarn("failed to resolve mvn version of %s, err=%v", repo.Name, err)
+	} else if property != "" {
+		repo.Maven.Version, repo.Maven.VersionProperty, repo.Maven.Vln = value, property, ln
+	} else if value != repo.Maven.Version {
+		repo.Maven.Version, repo.Maven.VersionExpr = value, repo.Maven.Version
+	}
+	value, property, ln, err = resolveVersion(repoPath, repo.Maven.ParentVersion)
+	if err != nil {
+		logWarn("failed to resolve mvn parent version of %s, err=%v", repo.Name, err)
+	} else if property != "" {
+		repo.Maven.ParentVersion, repo.Maven.ParentVersionProperty, repo.Maven.Pvln = value, property, ln
+	} else if value != repo.Maven.ParentVersion {
+		repo.Maven.ParentVersion, repo.Maven.ParentVersionExpr = value, repo.Maven.ParentVersion
+	}
+	return nil
+}
+
+// the reason the version, raw as read from pom.xml when expr is set, can't
+// be rewritten: only a literal or a single property can
+func checkSettable(version string, property string, expr string) error {
+	if expr != "" {
+		return fmt.Errorf("%s is an expression of properties, change them by hand", expr)
+	}
+	if property == "" && strings.Contains(version, "${") {
+		return fmt.Errorf("%s uses a property that is not defined", version)
+	}
 	return nil
 }
 
+// rewrites the version at lineNum of pom.xml, or the definition of the
+// property it comes from
+func setMvnValue(repoPath string, property string, version string, lineNum string) ([]byte, error) {
+	if property != "" && lineNum == "" {
+		return nil, setMavenConfigProperty(repoPath, property, version)
+	}
+	tag := "version"
+	if property != "" {
+		tag = property
+	}
+	cmd := exec.Command(
+		"sed",
+		updateMvn(tag, version, lineNum)...,
+	)
+
+	logDebug("%s", cmd)
+	cmd.Dir = repoPath
+	return runCmd(cmd)
+}
+
 // mvn versions:set -DnewVersion=<version>
 func updateMvnVersion(repoPath string, version string, lineNum string, repo *Repo) error {
 	var (
@@ -49,22 +191,18 @@ func updateMvnVersion(repoPath string, version string, lineNum string, repo *Rep
 		err    error
 	)
 
-	cmd := exec.Command(
-		// "sed",
-		// "-i",
-		// "",
-		// fmt.Sprintf("%ss#^\\([ \\t]*\\).*#\\1<version>%s</version>#", lineNum, version),
-		// "pom.xml",
-		"sed",
-		updateMvn(version, lineNum)...,
-	)
-
-	fmt.Println(cmd)
-	cmd.Dir = repoPath
-	output, err = cmd.Output()
+	err = checkSettable(repo.Maven.Version, repo.Maven.VersionProperty, repo.Maven.VersionExpr)
+	// the modules go first so a module that fails leaves the root, and
+	// with it the repo's version, as it was
+	if err == nil && repo.Maven.VersionProperty == "" {
+		err = updateModuleVersions(repoPath, repo.Maven.Version, version)
+	}
+	if err == nil {
+		output, err = setMvnValue(repoPath, repo.Maven.VersionProperty, version, lineNum)
+	}
 
 	if err != nil {
-		fmt.Printf("Output: %s, Error: %v\n", output, err)
+		logError("failed to update mvn version for %s, output=%s, err=%v", repo.Name, output, err)
 		return err
 	}
 	repo.Maven.Version = strings.TrimSpace(string(version))
@@ -77,16 +215,17 @@ func updateMvnParentVersion(repoPath string, version string, lineNum string, rep
 		output []byte
 		err    error
 	)
-	cmd := exec.Command(
-		"sed",
-		updateMvn(version, lineNum)...,
-	)
-
-	cmd.Dir = repoPath
-	output, err = cmd.Output()
+	err = checkSettable(repo.Maven.ParentVersion, repo.Maven.ParentVersionProperty, repo.Maven.ParentVersionExpr)
+	// modules first, see updateMvnVersion
+	if err == nil && repo.Maven.ParentVersionProperty == "" {
+		err = updateModuleParentVersions(repoPath, repo.Maven.ParentVersion, version)
+	}
+	if err == nil {
+		output, err = setMvnValue(repoPath, repo.Maven.ParentVersionProperty, version, lineNum)
+	}
 
 	if err != nil {
-		fmt.Printf("Output: %s, Error: %v\n", output, err)
+		logError("failed to update mvn parent version for %s, output=%s, err=%v", repo.Name, output, err)
 		return err
 	}
 	repo.Maven.ParentVersion = strings.TrimSpace(string(version))
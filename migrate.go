@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// bump when Config changes shape and append the migration from the previous
+// version to migrations
+const configSchemaVersion = 3
+
+// returned for configs written by a newer massgit, those are left untouched
+var errNewerConfig = errors.New("config was written by a newer version of massgit")
+
+// a migration upgrades the raw json of a config by exactly one version, it
+// works on the generic form so fields that no longer exist in Config can
+// still be read and moved
+type migration func(raw map[string]any) error
+
+// migrations[i] upgrades a config from schema version i to i+1
+var migrations = []migration{
+	migrateV0ToV1,
+	migrateV1ToV2,
+	migrateV2ToV3,
+}
+
+// upgrades the serialized config to configSchemaVersion, returning the
+// version it was stored with
+func migrateConfig(bytes []byte) ([]byte, int, error) {
+	var raw map[string]any
+	err := json.Unmarshal(bytes, &raw)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	version := 0
+	if v, ok := raw["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version > configSchemaVersion {
+		return nil, version, fmt.Errorf("%w: schema version %d, supported %d", errNewerConfig, version, configSchemaVersion)
+	}
+	if version == configSchemaVersion {
+		return bytes, version, nil
+	}
+
+	for v := version; v < configSchemaVersion; v++ {
+		err = migrations[v](raw)
+		if err != nil {
+			return nil, version, fmt.Errorf("migrating config from v%d to v%d: %w", v, v+1, err)
+		}
+		raw["schemaVersion"] = v + 1
+	}
+
+	bytes, err = json.Marshal(raw)
+	return bytes, version, err
+}
+
+// v0 configs were written without defaults when .massgit already existed
+// or the fields were never set, fill them in the way createConfig does
+func migrateV0ToV1(raw map[string]any) error {
+	if branch, _ := raw["branch"].(string); branch == "" {
+		raw["branch"] = "master"
+	}
+	if cols, _ := raw["cols"].(float64); cols <= 0 {
+		raw["cols"] = 4
+	}
+
+	repos, _ := raw["repos"].([]any)
+	if repos == nil {
+		repos = []any{}
+		raw["repos"] = repos
+	}
+	if visible, _ := raw["visibleRepos"].([]any); visible == nil {
+		visible = []any{}
+		for i, r := range repos {
+			repo, ok := r.(map[string]any)
+			if !ok {
+				return fmt.Errorf("repo %d is not an object", i)
+			}
+			if selected, _ := repo["selected"].(bool); selected {
+				visible = append(visible, i)
+			}
+		}
+		raw["visibleRepos"] = visible
+	}
+	return nil
+}
+
+// v2 adds profiles, the existing selection, branch and versions become the
+// default profile
+func migrateV1ToV2(raw map[string]any) error {
+	selected := []any{}
+	repos, _ := raw["repos"].([]any)
+	for i, r := range repos {
+		repo, ok := r.(map[string]any)
+		if !ok {
+			return fmt.Errorf("repo %d is not an object", i)
+		}
+		if isSelected, _ := repo["selected"].(bool); isSelected {
+			selected = append(selected, repo["name"])
+		}
+	}
+
+	raw["profiles"] = map[string]any{
+		defaultProfile: map[string]any{
+			"selected":      selected,
+			"branch":        raw["branch"],
+			"version":       raw["version"],
+			"parentVersion": raw["parentVersion"],
+		},
+	}
+	raw["activeProfile"] = defaultProfile
+	return nil
+}
+
+// v3 replaces the modified flag with the status counts, which only a
+// reload can fill in, so the flag is dropped
+func migrateV2ToV3(raw map[string]any) error {
+	repos, _ := raw["repos"].([]any)
+	for i, r := range repos {
+		repo, ok := r.(map[string]any)
+		if !ok {
+			return fmt.Errorf("repo %d is not an object", i)
+		}
+		delete(repo, "modified")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		migrated bool
+		want     Config
+	}{
+		{
+			name:     "v0 gets defaults, a default profile and loses modified",
+			config:   `{"repos":[{"name":"a","selected":true,"modified":true},{"name":"b","selected":false}],"version":"1.0"}`,
+			migrated: true,
+			want: Config{
+				SchemaVersion: configSchemaVersion,
+				Repos:         []Repo{{Name: "a", Selected: true}, {Name: "b"}},
+				VisibleRepos:  []int{0},
+				Branch:        "master",
+				Version:       "1.0",
+				Cols:          4,
+				Profiles: map[string]Profile{
+					defaultProfile: {Selected: []string{"a"}, Branch: "master", Version: "1.0"},
+				},
+				ActiveProfile: defaultProfile,
+			},
+		},
+		{
+			name:     "v1 keeps its settings and gets a default profile",
+			config:   `{"schemaVersion":1,"repos":[{"name":"a","selected":false},{"name":"b","selected":true,"modified":false}],"visibleRepos":[1],"branch":"dev","version":"2.0","parentVersion":"1.0","prefix":"p-","cols":3}`,
+			migrated: true,
+			want: Config{
+				SchemaVersion: configSchemaVersion,
+				Repos:         []Repo{{Name: "a"}, {Name: "b", Selected: true}},
+				VisibleRepos:  []int{1},
+				Branch:        "dev",
+				Version:       "2.0",
+				ParentVersion: "1.0",
+				Prefix:        "p-",
+				Cols:          3,
+				Profiles: map[string]Profile{
+					defaultProfile: {Selected: []string{"b"}, Branch: "dev", Version: "2.0", ParentVersion: "1.0"},
+				},
+				ActiveProfile: defaultProfile,
+			},
+		},
+		{
+			name:     "v2 keeps its profiles and loses modified",
+			config:   `{"schemaVersion":2,"repos":[{"name":"a","selected":true,"modified":true}],"visibleRepos":[0],"branch":"main","cols":2,"profiles":{"release":{"selected":["a"],"branch":"release"}},"activeProfile":"release"}`,
+			migrated: true,
+			want: Config{
+				SchemaVersion: configSchemaVersion,
+				Repos:         []Repo{{Name: "a", Selected: true}},
+				VisibleRepos:  []int{0},
+				Branch:        "main",
+				Cols:          2,
+				Profiles: map[string]Profile{
+					"release": {Selected: []string{"a"}, Branch: "release"},
+				},
+				ActiveProfile: "release",
+			},
+		},
+		{
+			name:   "current version is left as is",
+			config: `{"schemaVersion":3,"repos":[],"visibleRepos":[],"branch":"main","cols":4,"profiles":{},"activeProfile":"default"}`,
+			want: Config{
+				SchemaVersion: configSchemaVersion,
+				Repos:         []Repo{},
+				VisibleRepos:  []int{},
+				Branch:        "main",
+				Cols:          4,
+				Profiles:      map[string]Profile{},
+				ActiveProfile: defaultProfile,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, migrated, err := parseAndMigrateConfig([]byte(tt.config))
+			if err != nil {
+				t.Fatalf("parseAndMigrateConfig() err = %v", err)
+			}
+			if migrated != tt.migrated {
+				t.Errorf("migrated = %t, want %t", migrated, tt.migrated)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("config = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateConfigNewer(t *testing.T) {
+	_, _, err := parseAndMigrateConfig([]byte(`{"schemaVersion":99,"repos":[]}`))
+	if !errors.Is(err, errNewerConfig) {
+		t.Fatalf("err = %v, want errNewerConfig", err)
+	}
+}
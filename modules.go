@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// a module of a multi-module repo, path is relative to the repo
+type mvnModule struct {
+	path string
+	pom  Pom
+}
+
+func (m mvnModule) pomPath(repoPath string) string {
+	return filepath.Join(repoPath, m.path, "pom.xml")
+}
+
+// the version the module builds, inherited from its parent when the pom
+// doesn't set one
+func (m mvnModule) version() string {
+	if m.pom.Version == "" {
+		return m.pom.expand(m.pom.Parent.Version)
+	}
+	return m.pom.expand(m.pom.Version)
+}
+
+// finds the <modules> of the repo's root pom and of their poms in turn
+func discoverModules(repoPath string) ([]mvnModule, error) {
+	root, err := readRepoPom(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	var modules []mvnModule
+	seen := map[string]bool{".": true}
+	var walk func(dir string, pom Pom) error
+	walk = func(dir string, pom Pom) error {
+		for _, name := range pom.Modules {
+			path := filepath.ToSlash(filepath.Clean(filepath.Join(dir, strings.TrimSpace(name))))
+			// a module may also name its pom file instead of its directory
+			path = strings.TrimSuffix(path, "/pom.xml")
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			module := mvnModule{path: path}
+			module.pom, err = readPom(module.pomPath(repoPath))
+			if err != nil {
+				return fmt.Errorf("module %s: %w", path, err)
+			}
+			// properties are inherited from the aggregating pom
+			module.pom.Properties.Entries = append(module.pom.Properties.Entries, pom.Properties.Entries...)
+			modules = append(modules, module)
+			err = walk(path, module.pom)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return modules, walk(".", root)
+}
+
+// counts the repo's modules and notes those not at the repo's version
+func checkModules(repoPath string, maven *Maven) error {
+	maven.Modules = 0
+	maven.InconsistentModules = nil
+	modules, err := discoverModules(repoPath)
+	if err != nil {
+		return err
+	}
+	maven.Modules = len(modules)
+	for _, module := range modules {
+		if module.version() != maven.Version {
+			maven.InconsistentModules = append(maven.InconsistentModules, module.path)
+		}
+	}
+	return nil
+}
+
+// the artifacts built by the repo, the root pom's and its modules'
+func repoArtifacts(repoPath string, modules []mvnModule) map[string]bool {
+	keys := map[string]bool{}
+	if root, err := readRepoPom(repoPath); err == nil {
+		keys[root.key()] = true
+	}
+	for _, module := range modules {
+		keys[module.pom.key()] = true
+	}
+	return keys
+}
+
+var (
+	parentBlock = regexp.MustCompile(`(?s)<parent>.*?</parent>`)
+	// sections after which a <version> no longer belongs to the project
+	projectSections = regexp.MustCompile(`<(dependencies|dependencyManagement|build|profiles|reporting|properties)>`)
+)
+
+// moves the <parent> versions pointing at one of parents, and when
+// projectVersion is set the module's own and sibling dependency versions,
+// from one version to another. Values from properties are left to the
+// property's definition.
+func updateModulePom(path string, parents map[string]bool, from string, to string, projectVersion bool) (bool, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	pom, err := readPom(path)
+	if err != nil {
+		return false, err
+	}
+	content := string(bytes)
+	version := regexp.MustCompile(`<version>\s*` + regexp.QuoteMeta(from) + `\s*</version>`)
+	replace := func(block string) string {
+		key := pom.expand(tagValue(block, "groupId")) + ":" + tagValue(block, "artifactId")
+		if !parents[key] {
+			return block
+		}
+		return version.ReplaceAllString(block, "<version>"+to+"</version>")
+	}
+
+	updated := content
+	if loc := parentBlock.FindStringIndex(updated); loc != nil {
+		updated = updated[:loc[0]] + replace(updated[loc[0]:loc[1]]) + updated[loc[1]:]
+	}
+	if projectVersion {
+		end := len(updated)
+		if loc := projectSections.FindStringIndex(updated); loc != nil {
+			end = loc[0]
+		}
+		start := 0
+		if loc := parentBlock.FindStringIndex(updated[:end]); loc != nil {
+			start = loc[1]
+		}
+		if loc := version.FindStringIndex(updated[start:end]); loc != nil {
+			updated = updated[:start+loc[0]] + "<version>" + to + "</version>" + updated[start+loc[1]:]
+		}
+		updated = dependencyBlock.ReplaceAllStringFunc(updated, replace)
+	}
+	if updated == content {
+		return false, nil
+	}
+	return true, writeFileAtomic(path, []byte(updated), 0644)
+}
+
+// applies a version change of the repo's root pom to all of its modules
+func updateModuleVersions(repoPath string, from string, to string) error {
+	modules, err := discoverModules(repoPath)
+	if err != nil || len(modules) == 0 {
+		return err
+	}
+	parents := repoArtifacts(repoPath, modules)
+	var errs []error
+	for _, module := range modules {
+		changed, err := updateModulePom(module.pomPath(repoPath), parents, from, to, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %s: %w", module.path, err))
+		} else if changed {
+			logDebug("updated module %s of %s to %s", module.path, repoPath, to)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applies a parent version change of the repo's root pom to the modules
+// that share its parent
+func updateModuleParentVersions(repoPath string, from string, to string) error {
+	root, err := readRepoPom(repoPath)
+	if err != nil || root.parentKey() == "" {
+		return err
+	}
+	modules, err := discoverModules(repoPath)
+	if err != nil {
+		return err
+	}
+	parents := map[string]bool{root.parentKey(): true}
+	var errs []error
+	for _, module := range modules {
+		_, err := updateModulePom(module.pomPath(repoPath), parents, from, to, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %s: %w", module.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// the files a version change can touch, relative to the repo, for git add
+func versionFiles(repoPath string) []string {
+	files := []string{"pom.xml"}
+	modules, err := discoverModules(repoPath)
+	if err != nil {
+		logWarn("failed to find modules of %s, err=%v", repoPath, err)
+	}
+	for _, module := range modules {
+		files = append(files, filepath.ToSlash(filepath.Join(module.path, "pom.xml")))
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, mavenConfigFile)); err == nil {
+		files = append(files, mavenConfigFile)
+	}
+	return files
+}
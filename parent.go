@@ -0,0 +1,59 @@
+package main
+
+import "slices"
+
+// linkParents finds the workspace repos that are the <parent> of other
+// repos and flags children whose parent version the parent repo's pom
+// doesn't currently have
+func linkParents(repos []Repo) {
+	graph := buildDepGraph(repos)
+	for i := range repos {
+		repos[i].parent = ""
+		repos[i].isParent = false
+	}
+	mismatched := map[string]bool{}
+	for _, edge := range graph.edges {
+		if !edge.parent {
+			continue
+		}
+		child := slices.IndexFunc(repos, func(r Repo) bool { return r.Name == edge.from })
+		parent := slices.IndexFunc(repos, func(r Repo) bool { return r.Name == edge.to })
+		repos[child].parent = edge.to
+		repos[parent].isParent = true
+
+		pom := graph.poms[edge.to]
+		version := pom.expand(pom.Version)
+		if version != "" && edge.version != "" && edge.version != version {
+			mismatched[edge.from] = true
+			if !repos[child].parentMismatch {
+				logWarn("%s references parent %s %s but the parent repo is at %s", edge.from, edge.to, edge.version, version)
+			}
+		}
+	}
+	for i := range repos {
+		repos[i].parentMismatch = mismatched[repos[i].Name]
+	}
+}
+
+// true when some workspace repo is the parent of another, parent versions
+// are then only changed in its children
+func hasWorkspaceParent(repos []Repo) bool {
+	return slices.ContainsFunc(repos, func(r Repo) bool { return r.isParent })
+}
+
+// splits the selected repos into the parents, saved first so children
+// never point at a parent version that doesn't exist yet, and the rest
+func savePhases(repos []Repo) [][]int {
+	var parents, rest []int
+	for i, repo := range repos {
+		if !repo.Selected {
+			continue
+		}
+		if repo.isParent {
+			parents = append(parents, i)
+		} else {
+			rest = append(rest, i)
+		}
+	}
+	return [][]int{parents, rest}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+type pomDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+func (d pomDependency) key() string {
+	return d.GroupId + ":" + d.ArtifactId
+}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pomProperties struct {
+	Entries []pomProperty `xml:",any"`
+}
+
+// Pom is the part of a pom.xml massgit reads
+type Pom struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupId    string `xml:"groupId"`
+		ArtifactId string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+	Properties           pomProperties   `xml:"properties"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Modules              []string        `xml:"modules>module"`
+}
+
+func readPom(path string) (Pom, error) {
+	var pom Pom
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return pom, err
+	}
+	err = xml.Unmarshal(bytes, &pom)
+	return pom, err
+}
+
+// reads the repo's root pom, the properties of .mvn/maven.config come first
+// since they take precedence
+func readRepoPom(repoPath string) (Pom, error) {
+	pom, err := readPom(filepath.Join(repoPath, "pom.xml"))
+	if err != nil {
+		return pom, err
+	}
+	var entries []pomProperty
+	for name, value := range readMavenConfig(repoPath) {
+		entries = append(entries, pomProperty{XMLName: xml.Name{Local: name}, Value: value})
+	}
+	pom.Properties.Entries = append(entries, pom.Properties.Entries...)
+	return pom, nil
+}
+
+// the groupId, inherited from the parent when the pom doesn't set one
+func (p Pom) groupId() string {
+	if p.GroupId == "" {
+		return p.Parent.GroupId
+	}
+	return p.GroupId
+}
+
+func (p Pom) key() string {
+	return p.groupId() + ":" + p.ArtifactId
+}
+
+// the coordinates of the parent pom, empty without one
+func (p Pom) parentKey() string {
+	if p.Parent.ArtifactId == "" {
+		return ""
+	}
+	return p.Parent.GroupId + ":" + p.Parent.ArtifactId
+}
+
+func (p Pom) property(name string) (string, bool) {
+	for _, prop := range p.Properties.Entries {
+		if prop.XMLName.Local == name {
+			return prop.Value, true
+		}
+	}
+	return "", false
+}
+
+var placeholder = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// replaces ${name} with the pom's own properties and project.version,
+// leaving references it can't resolve as they are
+func (p Pom) expand(value string) string {
+	for range 10 {
+		expanded := placeholder.ReplaceAllStringFunc(value, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			resolved, ok := p.property(name)
+			switch name {
+			case "project.version", "pom.version":
+				resolved, ok = p.Version, p.Version != ""
+			case "project.groupId", "pom.groupId":
+				resolved, ok = p.groupId(), p.groupId() != ""
+			case "project.artifactId", "pom.artifactId":
+				resolved, ok = p.ArtifactId, true
+			}
+			if !ok {
+				return ref
+			}
+			return resolved
+		})
+		if expanded == value {
+			return value
+		}
+		value = expanded
+	}
+	return value
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultProfile = "default"
+
+// Profile is a named set of selection, branch and versions within a
+// workspace, the active one is mirrored in the top level Config fields
+type Profile struct {
+	Selected      []string `json:"selected"`
+	Branch        string   `json:"branch,omitempty"`
+	Version       string   `json:"version,omitempty"`
+	ParentVersion string   `json:"parentVersion,omitempty"`
+}
+
+// captures the live selection, branch and versions as a profile
+func (c Config) currentProfile() Profile {
+	p := Profile{
+		Selected:      []string{},
+		Branch:        c.stored.Branch,
+		Version:       c.Version,
+		ParentVersion: c.ParentVersion,
+	}
+	if c.sources == nil || c.source("branch") == sourceWorkspace {
+		p.Branch = c.Branch
+	}
+	for _, repo := range c.Repos {
+		if repo.Selected {
+			p.Selected = append(p.Selected, repo.Name)
+		}
+	}
+	return p
+}
+
+// returns the config with the live values stored in its active profile
+func (c Config) withActiveProfile() Config {
+	c.Profiles = maps.Clone(c.Profiles)
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	if c.ActiveProfile == "" {
+		c.ActiveProfile = defaultProfile
+	}
+	c.Profiles[c.ActiveProfile] = c.currentProfile()
+	return c
+}
+
+func (c Config) profileNames() []string {
+	return slices.Sorted(maps.Keys(c.Profiles))
+}
+
+// stores the live values into the active profile and loads name in their
+// place, a branch from env or flags keeps precedence over the profile
+func (c *Config) switchProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("no profile named %q, have %v", name, c.profileNames())
+	}
+	c.Profiles = maps.Clone(c.Profiles)
+	if c.ActiveProfile != "" {
+		c.Profiles[c.ActiveProfile] = c.currentProfile()
+	}
+	c.ActiveProfile = name
+
+	c.Version = p.Version
+	c.ParentVersion = p.ParentVersion
+	if p.Branch != "" {
+		c.stored.Branch = p.Branch
+		if source := c.source("branch"); source != sourceEnv && source != sourceFlag {
+			c.Branch = p.Branch
+			c.setWorkspaceValue("branch")
+		}
+	}
+
+	c.VisibleRepos = []int{}
+	for i := range c.Repos {
+		c.Repos[i].Selected = slices.Contains(p.Selected, c.Repos[i].Name)
+		if c.Repos[i].Selected {
+			c.VisibleRepos = append(c.VisibleRepos, i)
+		}
+	}
+	logInfo("switched to profile %s", name)
+	return nil
+}
+
+// creates a profile from the live values and makes it active
+func (c *Config) createProfile(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is empty")
+	}
+	if _, ok := c.Profiles[name]; ok {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	c.Profiles = maps.Clone(c.Profiles)
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	if c.ActiveProfile != "" {
+		c.Profiles[c.ActiveProfile] = c.currentProfile()
+	}
+	c.Profiles[name] = c.currentProfile()
+	c.ActiveProfile = name
+	return nil
+}
+
+func (c *Config) deleteProfile(name string) error {
+	if name == c.ActiveProfile {
+		return fmt.Errorf("can't delete the active profile %q", name)
+	}
+	c.Profiles = maps.Clone(c.Profiles)
+	delete(c.Profiles, name)
+	return nil
+}
+
+func (m SettingsModel) updateProfiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	names := m.config.profileNames()
+	if len(names) == 0 && !slices.Contains([]string{"ctrl+c", "q", "esc", "n"}, msg.String()) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.state = repoView
+	case "up", "k":
+		m.profileCursor = positiveMod(m.profileCursor-1, len(names))
+	case "down", "j":
+		m.profileCursor = positiveMod(m.profileCursor+1, len(names))
+	case "enter", " ":
+		err := m.config.switchProfile(names[m.profileCursor])
+		if err != nil {
+			m.msg = err.Error()
+		} else {
+			m.msg = fmt.Sprintf("switched to %s, s: apply to repos", names[m.profileCursor])
+		}
+		m.state = repoView
+	case "n":
+		m.profileName.SetValue("")
+		m.profileName.Focus()
+		m.state = profileNameView
+	case "d":
+		err := m.config.deleteProfile(names[m.profileCursor])
+		if err != nil {
+			m.msg = err.Error()
+		}
+		m.profileCursor = min(m.profileCursor, len(m.config.Profiles)-1)
+	}
+	return m, nil
+}
+
+func (m SettingsModel) viewProfiles() string {
+	var s string = "Profiles:\n\n"
+	for i, name := range m.config.profileNames() {
+		cursor := " "
+		if i == m.profileCursor {
+			cursor = selectedStyle.Render(">")
+		}
+		active := ""
+		if name == m.config.ActiveProfile {
+			active = " (active)"
+		}
+		p := m.config.Profiles[name]
+		s += fmt.Sprintf("%s %s%s - %d repos, branch: %s, ver: %s, parent ver: %s\n",
+			cursor, name, active, len(p.Selected), p.Branch, p.Version, p.ParentVersion)
+	}
+	if m.msg != "" {
+		s += "\n" + msgStyle.Render(m.msg)
+	}
+	s += helpStyle.Render("\nenter: switch • n: new from current • d: delete • esc: back\n")
+	return s
+}
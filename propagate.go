@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// propagation is one dependency version in a sibling repo that follows the
+// new version of a workspace library
+type propagation struct {
+	repo     string
+	library  string
+	groupId  string
+	artifact string
+	from     string
+	to       string
+	// set when the dependency's version comes from this property, which is
+	// then rewritten instead of the dependency
+	property string
+}
+
+func (p propagation) String() string {
+	target := p.artifact
+	if p.property != "" {
+		target = fmt.Sprintf("${%s}", p.property)
+	}
+	return fmt.Sprintf("%s: %s %s -> %s", p.repo, target, p.from, p.to)
+}
+
+var propertyRef = regexp.MustCompile(`^\$\{([^}]+)\}$`)
+
+// the dependency versions across all workspace repos that point at a
+// library in versions but not at its new version yet
+func planPropagation(repos []Repo, versions map[string]string) []propagation {
+	if len(versions) == 0 {
+		return nil
+	}
+	graph := buildDepGraph(repos)
+	var plan []propagation
+	seen := map[string]bool{}
+	for _, edge := range graph.edges {
+		to, ok := versions[edge.to]
+		// an empty version is managed elsewhere, usually a parent
+		if !ok || edge.parent || to == "" || edge.raw == "" || edge.version == to {
+			continue
+		}
+		p := propagation{
+			repo:     edge.from,
+			library:  edge.to,
+			groupId:  edge.groupId,
+			artifact: edge.artifact,
+			from:     edge.raw,
+			to:       to,
+		}
+		if match := propertyRef.FindStringSubmatch(edge.raw); match != nil {
+			p.property = match[1]
+			p.from = edge.version
+		} else if strings.Contains(edge.raw, "${") {
+			logWarn("not propagating %s to %s, version %s is not a plain property", edge.artifact, edge.from, edge.raw)
+			continue
+		}
+		key := strings.Join([]string{p.repo, p.property, p.groupId, p.artifact, p.from}, "|")
+		if p.property != "" {
+			key = p.repo + "|" + p.property
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		plan = append(plan, p)
+	}
+	return plan
+}
+
+var dependencyBlock = regexp.MustCompile(`(?s)<dependency>.*?</dependency>`)
+
+func tagValue(block string, tag string) string {
+	match := regexp.MustCompile(`<` + tag + `>\s*([^<]*?)\s*</` + tag + `>`).FindStringSubmatch(block)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// rewrites the poms of the dependent repo and its modules, keeping their
+// formatting. A property is rewritten where it is first defined.
+func applyPropagation(p propagation) error {
+	repoPath := fmt.Sprintf("./%s", p.repo)
+	if _, ok := readMavenConfig(repoPath)[p.property]; ok && p.property != "" {
+		return setMavenConfigProperty(repoPath, p.property, p.to)
+	}
+	paths := []string{filepath.Join(repoPath, "pom.xml")}
+	modules, err := discoverModules(repoPath)
+	if err != nil {
+		return err
+	}
+	for _, module := range modules {
+		paths = append(paths, module.pomPath(repoPath))
+	}
+
+	changed := false
+	for _, path := range paths {
+		ok, err := applyPropagationTo(path, p)
+		if err != nil {
+			return err
+		}
+		changed = changed || ok
+		if ok && p.property != "" {
+			break
+		}
+	}
+	if !changed {
+		return fmt.Errorf("no %s version %s found in %s", p.artifact, p.from, p.repo)
+	}
+	return nil
+}
+
+var (
+	propertiesStart = regexp.MustCompile(`<properties>`)
+	// sections whose <properties> belong to a profile or a plugin rather
+	// than the project
+	nestedSections = []*regexp.Regexp{
+		regexp.MustCompile(`(?s)<profiles>.*?</profiles>`),
+		regexp.MustCompile(`(?s)<build>.*?</build>`),
+		regexp.MustCompile(`(?s)<reporting>.*?</reporting>`),
+	}
+)
+
+// the span between the project's own <properties> and </properties>
+func projectProperties(content string) (int, int, bool) {
+	var nested [][]int
+	for _, section := range nestedSections {
+		nested = append(nested, section.FindAllStringIndex(content, -1)...)
+	}
+	for _, loc := range propertiesStart.FindAllStringIndex(content, -1) {
+		if slices.ContainsFunc(nested, func(n []int) bool { return loc[0] > n[0] && loc[0] < n[1] }) {
+			continue
+		}
+		end := strings.Index(content[loc[1]:], "</properties>")
+		if end < 0 {
+			return 0, 0, false
+		}
+		return loc[1], loc[1] + end, true
+	}
+	return 0, 0, false
+}
+
+func applyPropagationTo(path string, p propagation) (bool, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	pom, err := readPom(path)
+	if err != nil {
+		return false, err
+	}
+	content := string(bytes)
+	var updated string
+
+	if p.property != "" {
+		start, end, ok := projectProperties(content)
+		if !ok {
+			return false, nil
+		}
+		name := regexp.QuoteMeta(p.property)
+		re := regexp.MustCompile(`(<` + name + `>)\s*[^<]*?\s*(</` + name + `>)`)
+		loc := re.FindStringSubmatchIndex(content[start:end])
+		if loc == nil {
+			return false, nil
+		}
+		updated = content[:start+loc[3]] + p.to + content[start+loc[4]:]
+	} else {
+		version := regexp.MustCompile(`<version>\s*` + regexp.QuoteMeta(p.from) + `\s*</version>`)
+		updated = dependencyBlock.ReplaceAllStringFunc(content, func(block string) string {
+			if tagValue(block, "artifactId") != p.artifact || pom.expand(tagValue(block, "groupId")) != p.groupId {
+				return block
+			}
+			return version.ReplaceAllString(block, "<version>"+p.to+"</version>")
+		})
+	}
+	if updated == content {
+		return false, nil
+	}
+	return true, writeFileAtomic(path, []byte(updated), 0644)
+}
+
+// after a save, points the dependents of every repo whose version changed
+// from before at the new version
+func propagateVersions(config *Config, before map[string]string, ma *MessageAccumulator) {
+	changed := map[string]string{}
+	for _, repo := range config.Repos {
+		if old, ok := before[repo.Name]; ok && old != repo.Maven.Version {
+			changed[repo.Name] = repo.Maven.Version
+		}
+	}
+	plan := planPropagation(config.Repos, changed)
+	errs := map[string][]error{}
+	for _, p := range plan {
+		err := applyPropagation(p)
+		if err != nil {
+			logError("failed to propagate %s, err=%v", p, err)
+			ma.add("failed to propagate %s, err=%v\n", p, err)
+		} else {
+			logInfo("propagated %s", p)
+		}
+		errs[p.repo] = append(errs[p.repo], err)
+	}
+
+	for i := range config.Repos {
+		repoErrs, ok := errs[config.Repos[i].Name]
+		if !ok {
+			continue
+		}
+		repoPath := fmt.Sprintf("./%s", config.Repos[i].Name)
+		invalidateCachedStatus(config.Repos[i].Name)
+		status, err := gitStatusV2(repoPath)
+		if err != nil {
+			repoErrs = append(repoErrs, err)
+		} else {
+			config.Repos[i].setStatus(status)
+		}
+		// keep the error of the save itself if this repo was saved too
+		if err := errors.Join(repoErrs...); err != nil || config.Repos[i].LastError == "" {
+			config.Repos[i].setError(err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyPropagationTo(t *testing.T) {
+	lib := propagation{repo: "app", library: "lib", groupId: "com.example", artifact: "lib", from: "1.0.0", to: "1.1.0"}
+	libProperty := lib
+	libProperty.property = "lib.version"
+
+	tests := []struct {
+		name    string
+		p       propagation
+		pom     string
+		want    string
+		changed bool
+	}{
+		{
+			name: "plain dependency version",
+			p:    lib,
+			pom: `<project>
+  <dependencies>
+    <dependency><groupId>com.example</groupId><artifactId>lib</artifactId><version>1.0.0</version></dependency>
+    <dependency><groupId>com.example</groupId><artifactId>other</artifactId><version>1.0.0</version></dependency>
+  </dependencies>
+</project>`,
+			want: `<project>
+  <dependencies>
+    <dependency><groupId>com.example</groupId><artifactId>lib</artifactId><version>1.1.0</version></dependency>
+    <dependency><groupId>com.example</groupId><artifactId>other</artifactId><version>1.0.0</version></dependency>
+  </dependencies>
+</project>`,
+			changed: true,
+		},
+		{
+			name: "managed dependency version",
+			p:    lib,
+			pom: `<project>
+  <groupId>com.example</groupId>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>${project.groupId}</groupId>
+        <artifactId>lib</artifactId>
+        <version>1.0.0</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+			want: `<project>
+  <groupId>com.example</groupId>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>${project.groupId}</groupId>
+        <artifactId>lib</artifactId>
+        <version>1.1.0</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+			changed: true,
+		},
+		{
+			name: "property version",
+			p:    libProperty,
+			pom: `<project>
+  <properties>
+    <lib.version>1.0.0</lib.version>
+  </properties>
+  <profiles>
+    <profile>
+      <properties><lib.version>0.9.0</lib.version></properties>
+    </profile>
+  </profiles>
+</project>`,
+			want: `<project>
+  <properties>
+    <lib.version>1.1.0</lib.version>
+  </properties>
+  <profiles>
+    <profile>
+      <properties><lib.version>0.9.0</lib.version></properties>
+    </profile>
+  </profiles>
+</project>`,
+			changed: true,
+		},
+		{
+			name: "property not found",
+			p:    libProperty,
+			pom: `<project>
+  <properties>
+    <other.version>1.0.0</other.version>
+  </properties>
+  <profiles>
+    <profile>
+      <properties><lib.version>1.0.0</lib.version></properties>
+    </profile>
+  </profiles>
+</project>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pom.xml")
+			err := os.WriteFile(path, []byte(tt.pom), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changed, err := applyPropagationTo(path, tt.p)
+			if err != nil {
+				t.Fatalf("applyPropagationTo() err = %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("changed = %t, want %t", changed, tt.changed)
+			}
+			bytes, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want
+			if !tt.changed {
+				want = tt.pom
+			}
+			if string(bytes) != want {
+				t.Errorf("pom = %s\nwant %s", bytes, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// asks home to refresh the selected repos, periodic ticks schedule the next.
+// gen is the config's refreshTick when the tick was scheduled, ticks of an
+// older chain are dropped so changing the interval never doubles them.
+type refreshTickMsg struct {
+	periodic bool
+	gen      int
+}
+
+// repo state loaded in the background, gen is the config's refreshGen when
+// the refresh started. Partial results cover only the repos they carry and
+// don't end a running full refresh.
+type refreshMsg struct {
+	repos   []Repo
+	gen     int
+	partial bool
+	timings timingRun
+}
+
+// parses Config.RefreshInterval, an empty value turns periodic refresh off
+func refreshInterval(config *Config) (time.Duration, bool) {
+	if config.RefreshInterval == "" {
+		return 0, false
+	}
+	interval, err := time.ParseDuration(config.RefreshInterval)
+	if err != nil || interval <= 0 {
+		return defaultTime, true
+	}
+	return interval, true
+}
+
+func nextRefreshTick(config *Config) tea.Cmd {
+	interval, ok := refreshInterval(config)
+	if !ok {
+		return nil
+	}
+	gen := config.refreshTick
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{periodic: true, gen: gen}
+	})
+}
+
+// loads the selected repos' state on copies so the grid stays responsive,
+// the results are merged back by name in mergeRefresh
+func refreshRepos(repos []Repo, gen int) tea.Cmd {
+	repos = slices.Clone(repos)
+	return func() tea.Msg {
+		start := time.Now()
+		ma := &MessageAccumulator{}
+		var wg sync.WaitGroup
+		for i := range repos {
+			if !repos[i].Selected {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				updateRepo(&repos[i], ma, true)
+			}()
+		}
+		wg.Wait()
+		logDebug("%s", ma)
+		logDebug("background refresh of %d repos in %dms", len(repos), time.Since(start).Milliseconds())
+		return refreshMsg{repos: repos, gen: gen, timings: ma.run("refresh")}
+	}
+}
+
+// copies refreshed state into config, keeping choices made in the meantime
+// such as the selection
+func mergeRefresh(config *Config, msg refreshMsg) {
+	if !msg.partial && msg.gen == config.refreshGen {
+		config.timings = msg.timings
+	}
+	// linking re-reads every pom of the workspace, a partial refresh of a
+	// watched repo only needs it when one of its versions moved
+	relink := !msg.partial
+	for _, updated := range msg.repos {
+		idx := slices.IndexFunc(config.Repos, func(r Repo) bool {
+			return r.Name == updated.Name
+		})
+		if idx < 0 {
+			continue
+		}
+		repo := &config.Repos[idx]
+		if !msg.partial {
+			repo.stale = false
+		}
+		if msg.gen != config.refreshGen || !updated.Selected {
+			continue
+		}
+		if repo.Maven.Version != updated.Maven.Version || repo.Maven.ParentVersion != updated.Maven.ParentVersion {
+			relink = true
+		}
+		repo.Branch = updated.Branch
+		repo.Status = updated.Status
+		repo.Maven = updated.Maven
+		repo.LastError = updated.LastError
+	}
+	if msg.gen == config.refreshGen && relink {
+		linkParents(config.Repos)
+	}
+}
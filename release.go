@@ -0,0 +1,355 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type releaseStep uint
+
+const (
+	releaseSetVersion releaseStep = iota
+	releaseCommit
+	releaseTag
+	releaseNextVersion
+	releaseNextCommit
+	releasePush
+	releaseDone
+)
+
+func (s releaseStep) String() string {
+	switch s {
+	case releaseSetVersion:
+		return "set release version"
+	case releaseCommit:
+		return "commit release"
+	case releaseTag:
+		return "tag"
+	case releaseNextVersion:
+		return "set next snapshot"
+	case releaseNextCommit:
+		return "commit next snapshot"
+	case releasePush:
+		return "push"
+	default:
+		return "done"
+	}
+}
+
+type releaseState uint
+
+const (
+	releasePreview releaseState = iota
+	releaseRunning
+	releaseFinished
+)
+
+// one repo's progress through the release steps
+type releaseRepo struct {
+	idx     int
+	name    string
+	release string
+	next    string
+	tag     string
+	step    releaseStep
+	running bool
+	skipped bool
+	err     error
+	// set when the release guard only warned, kept as the repo's LastError
+	warning string
+	start   time.Time
+	elapsed time.Duration
+}
+
+func (r releaseRepo) finished() bool {
+	return r.step == releaseDone || r.skipped
+}
+
+// sent after each step, repo carries the state the step left the repo in
+type releaseStepMsg struct {
+	i       int
+	repo    Repo
+	err     error
+	warning string
+}
+
+// ReleaseModel walks the selected repos through set release version,
+// commit, tag, next snapshot, commit and push, pausing a repo on the first
+// failure until it is retried or skipped
+type ReleaseModel struct {
+	config  *Config
+	state   releaseState
+	repos   []releaseRepo
+	cursor  int
+	aborted bool
+	start   time.Time
+}
+
+func NewRelease(config *Config) ReleaseModel {
+	return ReleaseModel{
+		config: config,
+	}
+}
+
+// plans the release of the selected repos from their current versions
+func (m ReleaseModel) reset() ReleaseModel {
+	m.state = releasePreview
+	m.repos = nil
+	m.cursor = 0
+	m.aborted = false
+	for i, repo := range m.config.Repos {
+		if !repo.Selected {
+			continue
+		}
+		r := releaseRepo{idx: i, name: repo.Name}
+		v, err := parseVersion(repo.Maven.Version)
+		if err != nil {
+			r.err = err
+			r.skipped = true
+		} else {
+			release := v.withQualifier(v.preRelease(), false)
+			r.release = release.String()
+			r.next = nextSnapshot(release).String()
+			r.tag = tagName(m.config, repo, r.release)
+		}
+		m.repos = append(m.repos, r)
+	}
+	return m
+}
+
+// runs the current step of repo i on a copy of the repo
+func (m ReleaseModel) runStep(i int) tea.Cmd {
+	r := m.repos[i]
+	repo := m.config.Repos[r.idx]
+	return func() tea.Msg {
+		repoPath := fmt.Sprintf("./%s", repo.Name)
+		var err error
+		var warning string
+		switch r.step {
+		case releaseSetVersion:
+			warning, err = guardRelease(m.config, repo, r.release)
+			if err == nil {
+				err = updateMvnVersion(repoPath, r.release, repo.Maven.Vln, &repo)
+			}
+		case releaseCommit:
+			err = commitPom(repoPath, fmt.Sprintf("release %s", r.release))
+		case releaseTag:
+			_, err = gitTag(repoPath, r.tag, fmt.Sprintf("release %s", r.release))
+		case releaseNextVersion:
+			err = updateMvnVersion(repoPath, r.next, repo.Maven.Vln, &repo)
+		case releaseNextCommit:
+			err = commitPom(repoPath, fmt.Sprintf("prepare next development version %s", r.next))
+		case releasePush:
+			_, err = gitPush(repoPath)
+			if err == nil {
+				_, err = gitPushTag(repoPath, r.tag)
+			}
+		}
+		if status, statusErr := gitStatusV2(repoPath); statusErr == nil {
+			repo.setStatus(status)
+		}
+		return releaseStepMsg{i: i, repo: repo, err: err, warning: warning}
+	}
+}
+
+func commitPom(repoPath string, msg string) error {
+	_, err := gitAdd(repoPath, versionFiles(repoPath)...)
+	if err != nil {
+		return err
+	}
+	_, err = gitCommit(repoPath, msg)
+	return err
+}
+
+// true once no step is running and every repo is done or skipped, or the
+// release was aborted
+func (m ReleaseModel) finished() bool {
+	for _, r := range m.repos {
+		if r.running || (!r.finished() && !m.aborted) {
+			return false
+		}
+	}
+	return true
+}
+
+// records the release in the history once every repo is done, skipped or
+// stopped by an abort
+func (m ReleaseModel) finish() ReleaseModel {
+	m.state = releaseFinished
+	outcomes := make([]RepoOutcome, 0, len(m.repos))
+	for _, r := range m.repos {
+		outcome := RepoOutcome{Name: r.name, Ok: r.step == releaseDone, Elapsed: r.elapsed.Milliseconds()}
+		if r.err != nil {
+			outcome.Error = fmt.Sprintf("%s: %v", r.step, r.err)
+		} else if !outcome.Ok {
+			outcome.Error = fmt.Sprintf("stopped before %s", r.step)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	recordHistory("release", m.start, outcomes)
+	m.config.refreshGen++
+	return m
+}
+
+func (m ReleaseModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ReleaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case releaseStepMsg:
+		r := &m.repos[msg.i]
+		repo := &m.config.Repos[r.idx]
+		repo.Maven = msg.repo.Maven
+		repo.setStatus(msg.repo.Status)
+		if msg.warning != "" {
+			r.warning = msg.warning
+		}
+		repo.setError(msg.err)
+		if msg.err == nil && r.warning != "" {
+			repo.LastError = r.warning
+		}
+		r.running = false
+		r.elapsed = time.Since(r.start)
+		if msg.err != nil {
+			r.err = msg.err
+			logError("release of %s failed at %s, err=%v", r.name, r.step, msg.err)
+		} else {
+			r.step++
+		}
+		var cmd tea.Cmd
+		if r.err == nil && !r.finished() && !m.aborted {
+			r.running = true
+			cmd = m.runStep(msg.i)
+		}
+		if m.finished() {
+			m = m.finish()
+		}
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = positiveMod(m.cursor-1, max(len(m.repos), 1))
+		case "down", "j":
+			m.cursor = positiveMod(m.cursor+1, max(len(m.repos), 1))
+		case "esc":
+			if m.state != releaseRunning {
+				m.config.state = homeView
+				return m, tea.ClearScreen
+			}
+		case "enter":
+			if m.state != releasePreview {
+				return m, nil
+			}
+			m.state = releaseRunning
+			m.start = time.Now()
+			// a refresh started before the release would show the old versions
+			m.config.refreshGen++
+			var cmds []tea.Cmd
+			for i := range m.repos {
+				if m.repos[i].skipped {
+					continue
+				}
+				m.repos[i].running = true
+				m.repos[i].start = time.Now()
+				cmds = append(cmds, m.runStep(i))
+			}
+			if len(cmds) == 0 {
+				m = m.finish()
+			}
+			return m, tea.Batch(cmds...)
+		case "r":
+			if m.state != releaseRunning || len(m.repos) == 0 {
+				return m, nil
+			}
+			r := &m.repos[m.cursor]
+			if r.err == nil || r.running || r.skipped {
+				return m, nil
+			}
+			r.err = nil
+			r.running = true
+			// the time spent waiting on the retry is not part of the release
+			r.start = time.Now().Add(-r.elapsed)
+			return m, m.runStep(m.cursor)
+		case "s":
+			if m.state != releaseRunning || len(m.repos) == 0 {
+				return m, nil
+			}
+			r := &m.repos[m.cursor]
+			if r.err == nil || r.running {
+				return m, nil
+			}
+			r.skipped = true
+			if m.finished() {
+				m = m.finish()
+			}
+		case "a":
+			if m.state != releaseRunning {
+				return m, nil
+			}
+			m.aborted = true
+			logWarn("release aborted")
+			if m.finished() {
+				m = m.finish()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m ReleaseModel) View() string {
+	var s string
+	switch m.state {
+	case releasePreview:
+		s += "Release the selected repos: set release version, commit, tag, set next snapshot, commit, push\n\n"
+	case releaseRunning:
+		s += "Releasing...\n\n"
+	case releaseFinished:
+		s += "Release finished, recorded in history\n\n"
+	}
+	if len(m.repos) == 0 {
+		s += "no repos selected\n"
+	}
+
+	for i, r := range m.repos {
+		cursor := " "
+		if i == m.cursor {
+			cursor = selectedStyle.Render(">")
+		}
+		var progress string
+		switch {
+		case r.skipped && r.release == "":
+			progress = fmt.Sprintf("skipped: %v", r.err)
+		case r.skipped:
+			progress = fmt.Sprintf("skipped at %s", r.step)
+		case r.err != nil:
+			progress = conflictStyle.Render(fmt.Sprintf("failed at %s", r.step))
+		case r.running:
+			progress = fmt.Sprintf("%s...", r.step)
+		case m.state == releasePreview:
+			progress = fmt.Sprintf("tag %s", r.tag)
+		default:
+			progress = r.step.String()
+		}
+		s += fmt.Sprintf("%s %-30s %s -> %s -> %s  %s\n", cursor, r.name,
+			m.config.Repos[r.idx].Maven.Version, r.release, r.next, progress)
+	}
+
+	if m.state == releaseRunning && m.cursor < len(m.repos) && m.repos[m.cursor].err != nil {
+		s += "\n" + errorStyle.Render(m.repos[m.cursor].err.Error())
+	}
+
+	switch m.state {
+	case releasePreview:
+		s += helpStyle.Render("\nenter: start • esc: back\n")
+	case releaseRunning:
+		s += helpStyle.Render("\njk mvmt • r: retry • s: skip • a: abort • q: exit\n")
+	default:
+		s += helpStyle.Render("\nesc: back • q: exit\n")
+	}
+	return s
+}
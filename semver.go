@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const snapshot = "SNAPSHOT"
+
+var versionPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-(.+))?$`)
+
+// trailing number of a qualifier such as RC1, M2 or beta-3
+var qualifierNumber = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// version is a maven style major.minor.patch[-qualifier] version, the
+// qualifier keeps any -SNAPSHOT suffix, e.g. "RC1-SNAPSHOT"
+type version struct {
+	major, minor, patch int
+	qualifier           string
+}
+
+func parseVersion(s string) (version, error) {
+	match := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return version{}, fmt.Errorf("%q is not a major.minor.patch version", s)
+	}
+	var v version
+	v.major, _ = strconv.Atoi(match[1])
+	v.minor, _ = strconv.Atoi(match[2])
+	v.patch, _ = strconv.Atoi(match[3])
+	v.qualifier = match[4]
+	return v, nil
+}
+
+func (v version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.qualifier != "" {
+		s += "-" + v.qualifier
+	}
+	return s
+}
+
+func (v version) isSnapshot() bool {
+	return v.qualifier == snapshot || strings.HasSuffix(v.qualifier, "-"+snapshot)
+}
+
+// the qualifier without -SNAPSHOT, e.g. "RC1" for 1.0.0-RC1-SNAPSHOT
+func (v version) preRelease() string {
+	q := strings.TrimSuffix(v.qualifier, snapshot)
+	return strings.TrimSuffix(q, "-")
+}
+
+func (v version) withQualifier(preRelease string, isSnapshot bool) version {
+	v.qualifier = preRelease
+	if isSnapshot {
+		if v.qualifier != "" {
+			v.qualifier += "-"
+		}
+		v.qualifier += snapshot
+	}
+	return v
+}
+
+// bumpAction computes the next version of a repo from its current one
+type bumpAction struct {
+	name  string
+	apply func(version) version
+}
+
+// major, minor and patch follow semver for pre-releases: 1.2.0-RC1 becomes
+// 1.2.0 on a minor bump since it is a pre-release of that version, -SNAPSHOT
+// is kept on all three
+var bumpActions = []bumpAction{
+	{"major", func(v version) version {
+		if v.preRelease() == "" || v.minor != 0 || v.patch != 0 {
+			v.major, v.minor, v.patch = v.major+1, 0, 0
+		}
+		return v.withQualifier("", v.isSnapshot())
+	}},
+	{"minor", func(v version) version {
+		if v.preRelease() == "" || v.patch != 0 {
+			v.minor, v.patch = v.minor+1, 0
+		}
+		return v.withQualifier("", v.isSnapshot())
+	}},
+	{"patch", func(v version) version {
+		if v.preRelease() == "" {
+			v.patch++
+		}
+		return v.withQualifier("", v.isSnapshot())
+	}},
+	{"toggle -SNAPSHOT", func(v version) version {
+		return v.withQualifier(v.preRelease(), !v.isSnapshot())
+	}},
+	{"next snapshot", nextSnapshot},
+}
+
+// the development version after releasing v: 1.2.3 -> 1.2.4-SNAPSHOT and
+// 1.2.3-RC1 -> 1.2.3-RC2-SNAPSHOT, a snapshot moves past its own release
+func nextSnapshot(v version) version {
+	v = v.withQualifier(v.preRelease(), false)
+	if match := qualifierNumber.FindStringSubmatch(v.preRelease()); match != nil {
+		n, _ := strconv.Atoi(match[2])
+		return v.withQualifier(fmt.Sprintf("%s%d", match[1], n+1), true)
+	}
+	v.patch++
+	return v.withQualifier("", true)
+}
+
+// the new version of every selected repo, repos whose version can't be
+// parsed map to an error instead
+type bumpPlan struct {
+	action   string
+	versions map[string]string
+	errs     map[string]error
+	// dependents following the new versions, see Config.PropagateVersions
+	propagations []propagation
+}
+
+func planBump(config *Config, action bumpAction) bumpPlan {
+	plan := bumpPlan{action: action.name, versions: map[string]string{}, errs: map[string]error{}}
+	for _, repo := range config.Repos {
+		if !repo.Selected {
+			continue
+		}
+		v, err := parseVersion(repo.Maven.Version)
+		if err != nil {
+			plan.errs[repo.Name] = err
+			continue
+		}
+		plan.versions[repo.Name] = action.apply(v).String()
+	}
+	if config.PropagateVersions {
+		plan.propagations = planPropagation(config.Repos, plan.versions)
+	}
+	return plan
+}
+
+// the version shared by every repo in the plan, empty when they differ
+func (p bumpPlan) common() string {
+	var common string
+	for _, v := range p.versions {
+		if common != "" && v != common {
+			return ""
+		}
+		common = v
+	}
+	return common
+}
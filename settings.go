@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
@@ -17,7 +18,7 @@ import (
 type settingsState uint
 
 const (
-	settingsCount int           = 5
+	settingsCount int           = 11
 	stageChanges  string        = "\nenter: stage\n"
 	repoView      settingsState = iota
 	reloadingView
@@ -27,10 +28,18 @@ const (
 	parentVersionView
 	prefixView
 	colsView
+	profileView
+	profileNameView
+	refreshView
+	bumpView
+	bumpPreviewView
+	buildCmdView
 )
 
 type (
 	errMsg error
+	// starts the save once the saving message is shown, see save
+	saveMsg struct{}
 )
 
 type SettingsModel struct {
@@ -39,9 +48,15 @@ type SettingsModel struct {
 	parentVersion textinput.Model
 	prefix        textinput.Model
 	cols          textinput.Model
+	profileName   textinput.Model
+	refresh       textinput.Model
+	buildCmd      textinput.Model
 	config        *Config
 	cursor        Cursor
 	state         settingsState
+	profileCursor int
+	bumpCursor    int
+	bump          bumpPlan
 	err           error
 	msg           string
 }
@@ -53,6 +68,9 @@ func NewSettings(config *Config) SettingsModel {
 		version:       textinput.New(),
 		parentVersion: textinput.New(),
 		cols:          textinput.New(),
+		profileName:   textinput.New(),
+		refresh:       textinput.New(),
+		buildCmd:      textinput.New(),
 		state:         repoView,
 		config:        config,
 	}
@@ -76,6 +94,18 @@ func NewSettings(config *Config) SettingsModel {
 	m.cols.CharLimit = 1
 	m.cols.Width = 20
 
+	m.profileName.Placeholder = "release-train"
+	m.profileName.CharLimit = 40
+	m.profileName.Width = 20
+
+	m.refresh.Placeholder = defaultTime.String()
+	m.refresh.CharLimit = 10
+	m.refresh.Width = 20
+
+	m.buildCmd.Placeholder = defaultBuildCommand
+	m.buildCmd.CharLimit = 200
+	m.buildCmd.Width = 40
+
 	return m
 }
 
@@ -87,64 +117,103 @@ func positiveMod(a, b int) int {
 	return (a%b + b) % b
 }
 
+// MessageAccumulator collects the messages and per step timings of repos
+// being updated concurrently
 type MessageAccumulator struct {
-	msg string
+	mu      sync.Mutex
+	msg     string
+	timings []Timing
 }
 
-func updateRepo(repo *Repo, m *MessageAccumulator) {
-	var wg sync.WaitGroup
+func (ma *MessageAccumulator) add(format string, args ...any) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	ma.msg += fmt.Sprintf(format, args...)
+}
 
-	wg.Add(3)
+// records how long step took for repo since start
+func (ma *MessageAccumulator) time(repo string, step string, start time.Time) {
+	elapsed := time.Since(start)
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	ma.msg += fmt.Sprintf("%s-%s: elapsed: %dms\n", repo, step, elapsed.Milliseconds())
+	ma.timings = append(ma.timings, Timing{Repo: repo, Step: step, Elapsed: elapsed})
+}
 
-	go func() {
-		defer wg.Done()
-		start1 := time.Now()
-		branch, err := gitBranch(fmt.Sprintf("./%s", repo.Name))
-		if err != nil {
-			m.msg += fmt.Sprintf("failed to get branch for %s, err=%v\n",
-				repo.Name, err)
-		} else {
-			repo.Branch = strings.TrimSpace(branch)
-			m.msg += fmt.Sprintf("t1: %s: elapsed: %dms\n", repo.Name,
-				time.Since(start1).Milliseconds())
-		}
-	}()
+func (ma *MessageAccumulator) String() string {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	return ma.msg
+}
+
+func (ma *MessageAccumulator) run(op string) timingRun {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	return timingRun{op: op, at: time.Now(), timings: slices.Clone(ma.timings)}
+}
+
+// loads the repo's status and versions, from the status cache when cached
+// is set and nothing it keys on changed. The key misses working tree edits
+// so an explicit reload doesn't use it.
+func updateRepo(repo *Repo, m *MessageAccumulator, cached bool) {
+	var (
+		repoPath          = fmt.Sprintf("./%s", repo.Name)
+		key               = statusCacheKey(repoPath)
+		wg                sync.WaitGroup
+		statusErr, mvnErr error
+	)
+
+	if cached && loadCachedStatus(repo, key) {
+		m.add("%s: unchanged, skipped\n", repo.Name)
+		return
+	}
+
+	wg.Add(2)
 
 	go func() {
 		defer wg.Done()
-		start2 := time.Now()
-		status, err := gitStatus(fmt.Sprintf("./%s", repo.Name))
+		start1 := time.Now()
+		status, err := gitStatusV2(repoPath)
 		if err != nil {
-			m.msg += fmt.Sprintf("failed to get status for %s, err=%v\n",
-				repo.Name, err)
+			statusErr = err
+			m.add("failed to get status for %s, err=%v\n", repo.Name, err)
 		} else {
-			repo.Modified = !(status == "")
-			m.msg += fmt.Sprintf("t2: %s: elapsed: %dms\n", repo.Name,
-				time.Since(start2).Milliseconds())
+			repo.setStatus(status)
+			m.time(repo.Name, stepStatus, start1)
 		}
 	}()
 
 	go func() {
 		defer wg.Done()
 		start3 := time.Now()
-		err := mvnVersion(fmt.Sprintf("./%s", repo.Name), repo)
+		err := mvnVersion(repoPath, repo)
+		if err == nil {
+			err = checkModules(repoPath, &repo.Maven)
+		}
 		if err != nil {
-			m.msg += fmt.Sprintf("failed to get mvn version for %s, err=%v\n",
-				repo.Name, err)
+			mvnErr = err
+			m.add("failed to get mvn version for %s, err=%v\n", repo.Name, err)
 		} else {
-			m.msg += fmt.Sprintf("t3: %s: elapsed: %dms\n", repo.Name,
-				time.Since(start3).Milliseconds())
+			m.time(repo.Name, stepMvnVersion, start3)
 		}
 	}()
 
 	wg.Wait()
+	err := errors.Join(statusErr, mvnErr)
+	repo.setError(err)
+	if err == nil {
+		storeCachedStatus(repo, key)
+	}
 }
 
-func saveRepo(repo *Repo, config *Config, ma *MessageAccumulator) {
+// saves repo to the config's branch and versions, workspaceParent tells
+// whether any repo is a workspace parent, see hasWorkspaceParent
+func saveRepo(repo *Repo, config *Config, workspaceParent bool, ma *MessageAccumulator) {
 	var (
 		repoPath string = fmt.Sprintf("./%s", repo.Name)
 		switched bool
 		err      error
+		errs     []error
 	)
 
 	if repo.Branch != config.Branch {
@@ -158,42 +227,122 @@ func saveRepo(repo *Repo, config *Config, ma *MessageAccumulator) {
 			switched, err = createGitBranch(repoPath, config.Branch)
 		}
 		if err != nil {
-			//todo log
-			ma.msg += "failed to switch branch"
+			errs = append(errs, err)
+			ma.add("failed to switch branch for %s, err=%v\n", repo.Name, err)
 		}
-		ma.msg += fmt.Sprintf("%s-git: elapsed: %dms\n", repo.Name, time.Since(start1).Milliseconds())
+		ma.time(repo.Name, stepBranch, start1)
 		if switched {
 			repo.Branch = strings.TrimSpace(config.Branch)
 		}
 	}
-	if repo.Maven.Version != config.Version {
+	version := config.Version
+	if repo.isParent && config.ParentVersion != "" {
+		version = config.ParentVersion
+	}
+	if repo.targetVersion != "" {
+		version = repo.targetVersion
+		repo.targetVersion = ""
+	}
+	if version != "" && repo.Maven.Version != version {
 		start2 := time.Now()
-		err = updateMvnVersion(repoPath, config.Version, repo.Maven.Vln, repo)
+		var warning string
+		warning, err = guardRelease(config, *repo, version)
+		if err == nil {
+			err = updateMvnVersion(repoPath, version, repo.Maven.Vln, repo)
+		}
 		if err != nil {
-			//todo log
-			ma.msg += fmt.Sprintf("failed to update mvn version, err=%v\n", err)
+			errs = append(errs, err)
+			ma.add("failed to update mvn version, err=%v\n", err)
+		}
+		if warning != "" {
+			errs = append(errs, errors.New(warning))
+			ma.add("%s\n", warning)
 		}
-		ma.msg += fmt.Sprintf("%s-ver: elapsed: %dms\n", repo.Name, time.Since(start2).Milliseconds())
+		ma.time(repo.Name, stepVersion, start2)
 	}
-	if repo.Maven.ParentVersion != config.ParentVersion {
+	// with a parent in the workspace only its children follow ParentVersion,
+	// other parents such as a framework's are left alone
+	followsParent := repo.parent != "" || !workspaceParent
+	if config.ParentVersion != "" && followsParent && repo.Maven.ParentVersion != config.ParentVersion {
 		start3 := time.Now()
 		err = updateMvnParentVersion(repoPath, config.ParentVersion, repo.Maven.Pvln, repo)
 		if err != nil {
-			//todo log
-			ma.msg += fmt.Sprintf("failed to update mvn parentversion, err=%v\n", err)
+			errs = append(errs, err)
+			ma.add("failed to update mvn parentversion, err=%v\n", err)
+		}
+		ma.time(repo.Name, stepParentVersion, start3)
+	}
+
+	if repo.Maven.Modules > 0 {
+		err = checkModules(repoPath, &repo.Maven)
+		if err != nil {
+			errs = append(errs, err)
 		}
-		ma.msg += fmt.Sprintf("%s-pver: elapsed: %dms\n", repo.Name, time.Since(start3).Milliseconds())
 	}
 
-	status, err := gitStatus(repoPath)
+	start4 := time.Now()
+	status, err := gitStatusV2(repoPath)
 	if err != nil {
-		fmt.Printf("failed to get status for %s, err=%v\n", repo.Name, err)
+		errs = append(errs, err)
+		logError("failed to get status for %s, err=%v", repo.Name, err)
 	} else {
-		repo.Modified = !(status == "")
-		// m.msg += fmt.Sprintf("t2: %s: elapsed: %dms\n", m.config.Repos[i].Name,
-		// 	time.Since(start2).Milliseconds())
+		repo.setStatus(status)
+		ma.time(repo.Name, stepStatus, start4)
 	}
+	repo.setError(errors.Join(errs...))
+}
 
+// saves the selected repos to the settings, then returns to the grid
+func (m SettingsModel) save() (tea.Model, tea.Cmd) {
+	var (
+		// repoPath string
+		wg sync.WaitGroup
+		// switched bool
+		// err      error
+	)
+	start := time.Now()
+	ma := &MessageAccumulator{}
+	outcomes := make([]RepoOutcome, len(m.config.Repos))
+	before := map[string]string{}
+	// clear(m.config.VisibleRepos)
+	m.config.VisibleRepos = make([]int, 0)
+	for i := range m.config.Repos {
+		if m.config.Repos[i].Selected {
+			m.config.VisibleRepos = append(m.config.VisibleRepos, i)
+			before[m.config.Repos[i].Name] = m.config.Repos[i].Maven.Version
+		}
+	}
+	linkParents(m.config.Repos)
+	// read before the phases start, saveRepo writes the repos concurrently
+	workspaceParent := hasWorkspaceParent(m.config.Repos)
+	for _, phase := range savePhases(m.config.Repos) {
+		for _, i := range phase {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				repoStart := time.Now()
+				saveRepo(&m.config.Repos[i], m.config, workspaceParent, ma)
+				outcomes[i] = newOutcome(&m.config.Repos[i], repoStart)
+			}()
+		}
+		wg.Wait()
+	}
+	if m.config.PropagateVersions {
+		propagateVersions(m.config, before, ma)
+	}
+	linkParents(m.config.Repos)
+	m.config.refreshGen++
+	recordHistory("save", start, outcomes)
+	m.state = repoView
+	m.msg = ""
+	logDebug("%s", ma)
+	m.config.timings = ma.run("save")
+	logInfo("saved %d repos in %dms", len(m.config.VisibleRepos), time.Since(start).Milliseconds())
+	// return m, nil
+	m.config.save()
+	m.config.state = homeView
+	m.msg = ""
+	return m, tea.ClearScreen
 }
 
 func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -201,7 +350,14 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
+	case saveMsg:
+		return m.save()
 	case tea.KeyMsg:
+		if m.state == profileView {
+			return m.updateProfiles(msg)
+		} else if m.state == bumpView || m.state == bumpPreviewView {
+			return m.updateBump(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			if m.state == repoView {
@@ -264,6 +420,7 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ma := &MessageAccumulator{}
 					// repoChan := make(chan Repo, len(m.config.Repos))
 					var wg sync.WaitGroup
+					outcomes := make([]RepoOutcome, len(m.config.Repos))
 
 					for i := range m.config.Repos {
 						if !m.config.Repos[i].Selected {
@@ -273,59 +430,39 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						wg.Add(1)
 						go func() {
 							defer wg.Done()
-							updateRepo(&m.config.Repos[i], ma)
+							repoStart := time.Now()
+							updateRepo(&m.config.Repos[i], ma, false)
+							outcomes[i] = newOutcome(&m.config.Repos[i], repoStart)
 						}()
 					}
 					wg.Wait()
+					linkParents(m.config.Repos)
+					m.config.refreshGen++
+					recordHistory("reload", start, outcomes)
 
 					sort.Slice(m.config.Repos, func(i, j int) bool {
 						return m.config.Repos[i].Name < m.config.Repos[j].Name
 					})
-					// log.Println(ma.msg)
+					logDebug("%s", ma)
+					m.config.timings = ma.run("reload")
 					// m.config.Repos = repos
 					elapsed := time.Since(start)
 					m.msg += fmt.Sprintf("reloaded in %dms", elapsed.Milliseconds())
 				}
 				m.state = repoView
 			}
+		case "b":
+			if m.state == repoView {
+				m.state = bumpView
+				return m, nil
+			}
 		case "s":
 			if m.state == repoView {
 				m.state = savingView
 				m.msg = "saving..."
 				return m, func() tea.Msg { return msg }
 			} else if m.state == savingView {
-				var (
-					// repoPath string
-					wg sync.WaitGroup
-					// switched bool
-					// err      error
-				)
-				start := time.Now()
-				ma := &MessageAccumulator{}
-				// clear(m.config.VisibleRepos)
-				m.config.VisibleRepos = make([]int, 0)
-				for i := range m.config.Repos {
-					if !m.config.Repos[i].Selected {
-						continue
-					}
-					m.config.VisibleRepos = append(m.config.VisibleRepos, i)
-					wg.Add(1)
-					go func() {
-						defer wg.Done()
-						saveRepo(&m.config.Repos[i], m.config, ma)
-					}()
-				}
-				wg.Wait()
-				m.state = repoView
-				m.msg = ""
-				// fmt.Println(m.config.String())
-				// fmt.Println(ma.msg)
-				fmt.Printf("elapsed: %dms\n", time.Since(start).Milliseconds())
-				// return m, nil
-				go m.config.save()
-				m.config.state = homeView
-				m.msg = ""
-				return m, tea.ClearScreen
+				return m.save()
 			}
 		case "enter", " ":
 			switch m.state {
@@ -353,15 +490,37 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.state = prefixView
 						m.prefix.Focus()
 						return m, nil
-					} else {
+					} else if m.cursor.row == 4 {
 						m.cols.SetValue(fmt.Sprintf("%d", m.config.Cols))
 						m.state = colsView
 						m.cols.Focus()
 						return m, nil
+					} else if m.cursor.row == 5 {
+						m.profileCursor = max(slices.Index(m.config.profileNames(), m.config.ActiveProfile), 0)
+						m.state = profileView
+						return m, nil
+					} else if m.cursor.row == 6 {
+						m.refresh.SetValue(m.config.RefreshInterval)
+						m.state = refreshView
+						m.refresh.Focus()
+						return m, nil
+					} else if m.cursor.row == 7 {
+						next := (slices.Index(guardModes, m.config.releaseGuard()) + 1) % len(guardModes)
+						m.config.ReleaseGuard = guardModes[next]
+					} else if m.cursor.row == 8 {
+						m.config.CheckLocalRepo = !m.config.CheckLocalRepo
+					} else if m.cursor.row == 9 {
+						m.config.PropagateVersions = !m.config.PropagateVersions
+					} else {
+						m.buildCmd.SetValue(m.config.BuildCommand)
+						m.state = buildCmdView
+						m.buildCmd.Focus()
+						return m, nil
 					}
 				}
 			case branchView:
 				m.config.Branch = m.branch.Value()
+				m.config.setWorkspaceValue("branch")
 				m.branch.Blur()
 				m.state = repoView
 			case versionView:
@@ -374,6 +533,7 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = repoView
 			case prefixView:
 				m.config.Prefix = m.prefix.Value()
+				m.config.setWorkspaceValue("prefix")
 				m.prefix.Blur()
 				m.state = repoView
 			case colsView:
@@ -383,8 +543,30 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.config.Cols = cols
 				}
+				m.config.setWorkspaceValue("cols")
 				m.cols.Blur()
 				m.state = repoView
+			case refreshView:
+				m.config.RefreshInterval = strings.TrimSpace(m.refresh.Value())
+				// the pending tick belongs to the old interval, start over
+				m.config.refreshTick++
+				if interval, ok := refreshInterval(m.config); ok {
+					m.config.RefreshInterval = interval.String()
+					cmds = append(cmds, nextRefreshTick(m.config))
+				}
+				m.refresh.Blur()
+				m.state = repoView
+			case buildCmdView:
+				m.config.BuildCommand = strings.TrimSpace(m.buildCmd.Value())
+				m.buildCmd.Blur()
+				m.state = repoView
+			case profileNameView:
+				err := m.config.createProfile(m.profileName.Value())
+				if err != nil {
+					m.msg = err.Error()
+				}
+				m.profileName.Blur()
+				m.state = repoView
 			}
 		}
 	case errMsg:
@@ -402,9 +584,29 @@ func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 	m.cols, cmd = m.cols.Update(msg)
 	cmds = append(cmds, cmd)
+	m.profileName, cmd = m.profileName.Update(msg)
+	cmds = append(cmds, cmd)
+	m.refresh, cmd = m.refresh.Update(msg)
+	cmds = append(cmds, cmd)
+	m.buildCmd, cmd = m.buildCmd.Update(msg)
+	cmds = append(cmds, cmd)
 	return m, tea.Batch(cmds...)
 }
 
+// shows which layer a setting came from, see applyLayers
+func sourceLabel(config *Config, name string) string {
+	return helpStyle.Render(fmt.Sprintf("(%s)", config.source(name)))
+}
+
+// true while a text input has focus and keys should reach it untranslated
+func (m SettingsModel) editing() bool {
+	switch m.state {
+	case branchView, versionView, parentVersionView, prefixView, colsView, profileNameView, refreshView, buildCmdView:
+		return true
+	}
+	return false
+}
+
 func getCursor(cursor Cursor, row int, col int) string {
 	if cursor.column == col && cursor.row == row {
 		return selectedStyle.Render(">")
@@ -445,6 +647,28 @@ func (m SettingsModel) View() string {
 			m.cols.View(),
 		)
 		s += helpStyle.Render(stageChanges)
+	case refreshView:
+		s += fmt.Sprintf(
+			"Background refresh interval, empty turns it off:\n\n%s\n\n",
+			m.refresh.View(),
+		)
+		s += helpStyle.Render(stageChanges)
+	case buildCmdView:
+		s += fmt.Sprintf(
+			"Command the build runner runs in each repo:\n\n%s\n\n",
+			m.buildCmd.View(),
+		)
+		s += helpStyle.Render(stageChanges)
+	case profileView:
+		s += m.viewProfiles()
+	case bumpView, bumpPreviewView:
+		s += m.viewBump()
+	case profileNameView:
+		s += fmt.Sprintf(
+			"New profile from the current settings:\n\n%s\n\n",
+			m.profileName.View(),
+		)
+		s += helpStyle.Render("\nenter: create\n")
 	default:
 		// s += "\033[0J"
 		var sub = make([]string, 0, len(m.config.Repos))
@@ -455,14 +679,31 @@ func (m SettingsModel) View() string {
 				checked = "x"
 			}
 
-			sub = append(sub, fmt.Sprintf("%s [%s] %s\n", cursor, checked, repo.Name))
+			name := repo.Name
+			if repo.isParent {
+				name += helpStyle.Render(" (parent)")
+			}
+			sub = append(sub, fmt.Sprintf("%s [%s] %s\n", cursor, checked, name))
 		}
 		var b string
-		b += fmt.Sprintf("\t  %s branch: %s\n", getCursor(m.cursor, 0, 1), m.config.Branch)
+		b += fmt.Sprintf("\t  %s branch: %s %s\n", getCursor(m.cursor, 0, 1), m.config.Branch, sourceLabel(m.config, "branch"))
 		b += fmt.Sprintf("\t  %s ver: %s\n", getCursor(m.cursor, 1, 1), m.config.Version)
 		b += fmt.Sprintf("\t  %s parent ver: %s\n", getCursor(m.cursor, 2, 1), m.config.ParentVersion)
-		b += fmt.Sprintf("\t  %s hide prefix: %s\n", getCursor(m.cursor, 3, 1), m.config.Prefix)
-		b += fmt.Sprintf("\t  %s num of cols: %d\n", getCursor(m.cursor, 4, 1), m.config.Cols)
+		b += fmt.Sprintf("\t  %s hide prefix: %s %s\n", getCursor(m.cursor, 3, 1), m.config.Prefix, sourceLabel(m.config, "prefix"))
+		b += fmt.Sprintf("\t  %s num of cols: %d %s\n", getCursor(m.cursor, 4, 1), m.config.Cols, sourceLabel(m.config, "cols"))
+		b += fmt.Sprintf("\t  %s profile: %s\n", getCursor(m.cursor, 5, 1), m.config.ActiveProfile)
+		refresh := m.config.RefreshInterval
+		if refresh == "" {
+			refresh = "off"
+		}
+		b += fmt.Sprintf("\t  %s refresh: %s\n", getCursor(m.cursor, 6, 1), refresh)
+		b += fmt.Sprintf("\t  %s released version guard: %s\n", getCursor(m.cursor, 7, 1), m.config.releaseGuard())
+		b += fmt.Sprintf("\t  %s check ~/.m2 for releases: %t\n", getCursor(m.cursor, 8, 1), m.config.CheckLocalRepo)
+		b += fmt.Sprintf("\t  %s propagate to dependents: %t\n", getCursor(m.cursor, 9, 1), m.config.PropagateVersions)
+		b += fmt.Sprintf("\t  %s build command: %s\n", getCursor(m.cursor, 10, 1), m.config.buildCommand())
+		if path, err := userConfigPath(); err == nil {
+			b += helpStyle.Render(fmt.Sprintf("\n\t    user config: %s", path))
+		}
 
 		// b := fmt.Sprintf("\t  %s branch: %s\n\t  %s hide prefix: %s\n", getCursor(m.cursor, 0, 1), m.config.Branch, getCursor(m.cursor, 1, 1), m.config.Prefix)
 		s += lipgloss.JoinHorizontal(lipgloss.Top, strings.Join(sub, ""), b)
@@ -471,7 +712,7 @@ func (m SettingsModel) View() string {
 			s += msgStyle.Render(m.msg)
 		}
 		s += fmt.Sprintf("\n%v", m.cursor)
-		s += helpStyle.Render("\nenter: select • s: save • r: reload • q: exit\n")
+		s += helpStyle.Render("\nenter: select • s: save • r: reload • b: bump version • ctrl+l: logs • q: exit\n")
 	}
 
 	return s
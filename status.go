@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitStatus is the parsed output of git status --porcelain=v2 --branch
+type GitStatus struct {
+	Branch     string `json:"branch"`
+	Upstream   string `json:"upstream,omitempty"`
+	Ahead      int    `json:"ahead,omitempty"`
+	Behind     int    `json:"behind,omitempty"`
+	Detached   bool   `json:"detached,omitempty"`
+	Staged     int    `json:"staged,omitempty"`
+	Unstaged   int    `json:"unstaged,omitempty"`
+	Untracked  int    `json:"untracked,omitempty"`
+	Conflicted int    `json:"conflicted,omitempty"`
+	// merge, rebase, cherry-pick, revert or bisect while one is unfinished
+	Operation string `json:"operation,omitempty"`
+}
+
+func (s GitStatus) clean() bool {
+	return s.Staged == 0 && s.Unstaged == 0 && s.Untracked == 0 && s.Conflicted == 0
+}
+
+// run git --no-optional-locks status --porcelain=v2 --branch -z
+func gitStatusV2(repoPath string) (GitStatus, error) {
+	cmd := exec.Command("git", "--no-optional-locks", "status", "--porcelain=v2", "--branch", "-z")
+	cmd.Dir = repoPath
+	out, err := runCmd(cmd)
+	if err != nil {
+		return GitStatus{}, err
+	}
+	status := parseStatusV2(out)
+	status.Operation = gitOperation(repoPath)
+	return status, nil
+}
+
+// marker files git leaves in .git while an operation is unfinished
+var operationMarkers = []struct {
+	path      string
+	operation string
+}{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+	{"BISECT_LOG", "bisect"},
+}
+
+func gitOperation(repoPath string) string {
+	for _, marker := range operationMarkers {
+		if _, err := os.Stat(filepath.Join(repoPath, ".git", marker.path)); err == nil {
+			return marker.operation
+		}
+	}
+	return ""
+}
+
+// counts of staged (+), unstaged (~), untracked (?) and conflicted (!) files
+func summarizeStatus(status GitStatus) string {
+	if status.clean() {
+		return "clean"
+	}
+	var parts []string
+	if status.Staged > 0 {
+		parts = append(parts, stagedStyle.Render(fmt.Sprintf("+%d", status.Staged)))
+	}
+	if status.Unstaged > 0 {
+		parts = append(parts, unstagedStyle.Render(fmt.Sprintf("~%d", status.Unstaged)))
+	}
+	if status.Untracked > 0 {
+		parts = append(parts, helpStyle.Render(fmt.Sprintf("?%d", status.Untracked)))
+	}
+	if status.Conflicted > 0 {
+		parts = append(parts, conflictStyle.Render(fmt.Sprintf("!%d", status.Conflicted)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// parses the nul separated porcelain v2 format, see git-status(1)
+func parseStatusV2(out []byte) GitStatus {
+	var status GitStatus
+	fields := bytes.Split(out, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		line := string(fields[i])
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case '#':
+			key, value, _ := strings.Cut(strings.TrimPrefix(line, "# "), " ")
+			switch key {
+			case "branch.head":
+				status.Detached = value == "(detached)"
+				status.Branch = value
+			case "branch.upstream":
+				status.Upstream = value
+			case "branch.ab":
+				ahead, behind, _ := strings.Cut(value, " ")
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+			}
+		case '1', '2':
+			if len(line) > 4 {
+				if line[2] != '.' {
+					status.Staged++
+				}
+				if line[3] != '.' {
+					status.Unstaged++
+				}
+			}
+			// renames and copies are followed by the original path
+			if line[0] == '2' {
+				i++
+			}
+		case 'u':
+			status.Conflicted++
+		case '?':
+			status.Untracked++
+		}
+	}
+	return status
+}
+
+type statusCacheEntry struct {
+	key    string
+	loaded time.Time
+	branch string
+	status GitStatus
+	maven  Maven
+}
+
+// statusCache remembers what updateRepo loaded for each repo together with
+// the mtimes of HEAD, the checked out ref, the index and pom.xml. Edits to
+// tracked files don't touch any of them, so entries also expire after
+// defaultTime and the watcher drops a repo's entry when its files change.
+var statusCache = struct {
+	sync.Mutex
+	entries map[string]statusCacheEntry
+}{entries: map[string]statusCacheEntry{}}
+
+func mtime(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "-"
+	}
+	return strconv.FormatInt(info.ModTime().UnixNano(), 10)
+}
+
+// builds the cache key for the repo at repoPath from file mtimes
+func statusCacheKey(repoPath string) string {
+	gitDir := filepath.Join(repoPath, ".git")
+	parts := []string{
+		mtime(filepath.Join(gitDir, "HEAD")),
+		mtime(filepath.Join(gitDir, "index")),
+		mtime(filepath.Join(repoPath, "pom.xml")),
+		mtime(filepath.Join(repoPath, mavenConfigFile)),
+	}
+	// a module's pom changes its version without touching the root pom
+	modules, _ := discoverModules(repoPath)
+	for _, module := range modules {
+		parts = append(parts, mtime(module.pomPath(repoPath)))
+	}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err == nil {
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: "); ok {
+			parts = append(parts, mtime(filepath.Join(gitDir, filepath.FromSlash(ref))))
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// copies the cached state into repo if nothing changed since it was loaded
+func loadCachedStatus(repo *Repo, key string) bool {
+	statusCache.Lock()
+	defer statusCache.Unlock()
+	entry, ok := statusCache.entries[repo.Name]
+	if !ok || entry.key != key || time.Since(entry.loaded) > defaultTime {
+		return false
+	}
+	repo.Branch = entry.branch
+	repo.setStatus(entry.status)
+	repo.Maven = entry.maven
+	return true
+}
+
+func storeCachedStatus(repo *Repo, key string) {
+	statusCache.Lock()
+	defer statusCache.Unlock()
+	statusCache.entries[repo.Name] = statusCacheEntry{
+		key:    key,
+		loaded: time.Now(),
+		branch: repo.Branch,
+		status: repo.Status,
+		maven:  repo.Maven,
+	}
+}
+
+func invalidateCachedStatus(name string) {
+	statusCache.Lock()
+	defer statusCache.Unlock()
+	delete(statusCache.entries, name)
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultTagTemplate = "v{version}"
+
+// the tag a release of repo at version gets, from Config.TagTemplate where
+// {version} and {name} are replaced
+func tagName(config *Config, repo Repo, version string) string {
+	template := config.TagTemplate
+	if template == "" {
+		template = defaultTagTemplate
+	}
+	return strings.NewReplacer("{version}", version, "{name}", repo.Name).Replace(template)
+}
+
+type tagAction uint
+
+const (
+	tagCreate tagAction = iota
+	tagList
+	tagCheckout
+)
+
+var tagActions = []string{
+	"create annotated tags from the template",
+	"list which repos have a tag",
+	"check out a tag in detached mode",
+}
+
+type tagState uint
+
+const (
+	tagMenu tagState = iota
+	tagInput
+	tagResults
+)
+
+type tagResult struct {
+	name   string
+	tag    string
+	ok     bool
+	detail string
+}
+
+// TagModel creates, finds and checks out tags across the selected repos
+type TagModel struct {
+	config  *Config
+	state   tagState
+	action  tagAction
+	cursor  int
+	input   textinput.Model
+	results []tagResult
+}
+
+func NewTag(config *Config) TagModel {
+	m := TagModel{
+		config: config,
+		input:  textinput.New(),
+	}
+	m.input.CharLimit = 80
+	m.input.Width = 40
+	return m
+}
+
+func (m TagModel) reset() TagModel {
+	m.state = tagMenu
+	m.cursor = 0
+	m.results = nil
+	return m
+}
+
+// runs op on each selected repo concurrently and records it in history
+func (m TagModel) forSelected(op string, fn func(repo *Repo) tagResult) []tagResult {
+	var wg sync.WaitGroup
+	start := time.Now()
+	results := make([]tagResult, len(m.config.Repos))
+	outcomes := make([]RepoOutcome, len(m.config.Repos))
+	for i := range m.config.Repos {
+		if !m.config.Repos[i].Selected {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repoStart := time.Now()
+			results[i] = fn(&m.config.Repos[i])
+			outcomes[i] = newOutcome(&m.config.Repos[i], repoStart)
+		}()
+	}
+	wg.Wait()
+	if op != "" {
+		recordHistory(op, start, outcomes)
+	}
+
+	var selected []tagResult
+	for _, result := range results {
+		if result.name != "" {
+			selected = append(selected, result)
+		}
+	}
+	return selected
+}
+
+func (m TagModel) run() []tagResult {
+	value := strings.TrimSpace(m.input.Value())
+	switch m.action {
+	case tagCreate:
+		m.config.TagTemplate = value
+		return m.forSelected("tag", func(repo *Repo) tagResult {
+			tag := tagName(m.config, *repo, repo.Maven.Version)
+			// a tag names a release, a snapshot isn't one
+			v, err := parseVersion(repo.Maven.Version)
+			if err == nil && v.isSnapshot() {
+				err = fmt.Errorf("%s is a snapshot version, release it before tagging", repo.Maven.Version)
+			}
+			if err != nil {
+				repo.setError(err)
+				return tagResult{name: repo.Name, tag: tag, detail: firstLine(err)}
+			}
+			_, err = gitTag(fmt.Sprintf("./%s", repo.Name), tag, fmt.Sprintf("release %s", repo.Maven.Version))
+			repo.setError(err)
+			if err != nil {
+				return tagResult{name: repo.Name, tag: tag, detail: firstLine(err)}
+			}
+			return tagResult{name: repo.Name, tag: tag, ok: true, detail: "created"}
+		})
+	case tagList:
+		return m.forSelected("", func(repo *Repo) tagResult {
+			has, err := gitHasTag(fmt.Sprintf("./%s", repo.Name), value)
+			if err != nil {
+				return tagResult{name: repo.Name, tag: value, detail: firstLine(err)}
+			}
+			if !has {
+				return tagResult{name: repo.Name, tag: value, detail: "missing"}
+			}
+			return tagResult{name: repo.Name, tag: value, ok: true, detail: "present"}
+		})
+	default:
+		results := m.forSelected("checkout", func(repo *Repo) tagResult {
+			repoPath := fmt.Sprintf("./%s", repo.Name)
+			_, err := gitCheckoutDetached(repoPath, value)
+			repo.setError(err)
+			if status, statusErr := gitStatusV2(repoPath); statusErr == nil {
+				repo.setStatus(status)
+			}
+			if err != nil {
+				return tagResult{name: repo.Name, tag: value, detail: firstLine(err)}
+			}
+			return tagResult{name: repo.Name, tag: value, ok: true, detail: "checked out"}
+		})
+		m.config.refreshGen++
+		return results
+	}
+}
+
+// the command line of a cmdError is noise in a one line result
+func firstLine(err error) string {
+	msg := err.Error()
+	var cmdErr *cmdError
+	if errors.As(err, &cmdErr) && strings.TrimSpace(cmdErr.stderr) != "" {
+		msg = cmdErr.stderr
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	return line
+}
+
+func (m TagModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TagModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch m.state {
+	case tagMenu:
+		switch keyMsg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "T":
+			m.config.state = homeView
+			return m, tea.ClearScreen
+		case "up", "k":
+			m.cursor = positiveMod(m.cursor-1, len(tagActions))
+		case "down", "j":
+			m.cursor = positiveMod(m.cursor+1, len(tagActions))
+		case "enter", " ":
+			m.action = tagAction(m.cursor)
+			m.input.SetValue("")
+			if m.action == tagCreate {
+				m.input.SetValue(m.config.TagTemplate)
+				if m.config.TagTemplate == "" {
+					m.input.SetValue(defaultTagTemplate)
+				}
+			}
+			m.input.Focus()
+			m.state = tagInput
+		}
+		return m, nil
+	case tagInput:
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.input.Blur()
+			m.state = tagMenu
+			return m, nil
+		case "enter":
+			m.input.Blur()
+			m.results = m.run()
+			m.state = tagResults
+			return m, nil
+		}
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	default:
+		switch keyMsg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "enter":
+			m.state = tagMenu
+		}
+		return m, nil
+	}
+}
+
+func (m TagModel) View() string {
+	var s string = "Tags across the selected repos\n\n"
+	switch m.state {
+	case tagMenu:
+		for i, action := range tagActions {
+			cursor := " "
+			if i == m.cursor {
+				cursor = selectedStyle.Render(">")
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, action)
+		}
+		s += helpStyle.Render("\nenter: select • esc: back • q: exit\n")
+	case tagInput:
+		if m.action == tagCreate {
+			s += fmt.Sprintf("Tag name template, {version} and {name} are replaced:\n\n%s\n\n", m.input.View())
+			for _, repo := range m.config.Repos {
+				if repo.Selected {
+					s += fmt.Sprintf("  %-30s %s\n", repo.Name, tagName(&Config{TagTemplate: m.input.Value()}, repo, repo.Maven.Version))
+				}
+			}
+		} else {
+			s += fmt.Sprintf("Tag:\n\n%s\n\n", m.input.View())
+		}
+		s += helpStyle.Render("\nenter: run • esc: back\n")
+	default:
+		have := 0
+		for _, result := range m.results {
+			icon := "🔴"
+			if result.ok {
+				icon = "🟢"
+				have++
+			}
+			s += fmt.Sprintf("%s %-30s %-20s %s\n", icon, result.name, result.tag, result.detail)
+		}
+		if m.action == tagList {
+			s += fmt.Sprintf("\n%d of %d repos have the tag\n", have, len(m.results))
+		}
+		s += helpStyle.Render("\nenter: done • q: exit\n")
+	}
+	return s
+}
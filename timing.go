@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// steps timed by updateRepo and saveRepo
+const (
+	stepStatus        = "status"
+	stepMvnVersion    = "mvn version"
+	stepBranch        = "branch"
+	stepVersion       = "version"
+	stepParentVersion = "parent version"
+)
+
+var stepOrder = []string{stepStatus, stepMvnVersion, stepBranch, stepVersion, stepParentVersion}
+
+type Timing struct {
+	Repo    string
+	Step    string
+	Elapsed time.Duration
+}
+
+// the timings of one reload, refresh or save
+type timingRun struct {
+	op      string
+	at      time.Time
+	timings []Timing
+}
+
+// the steps that appear in the run, in stepOrder
+func (r timingRun) steps() []string {
+	var steps []string
+	for _, step := range stepOrder {
+		if slices.ContainsFunc(r.timings, func(t Timing) bool { return t.Step == step }) {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+type timingMode uint
+
+const (
+	byRepo timingMode = iota
+	byStep
+)
+
+// a table cell, value orders numeric columns and text the first one
+type timingCell struct {
+	text  string
+	value float64
+}
+
+func msCell(d time.Duration) timingCell {
+	if d == 0 {
+		return timingCell{text: "-"}
+	}
+	return timingCell{text: fmt.Sprintf("%dms", d.Milliseconds()), value: float64(d)}
+}
+
+// TimingModel shows the last run's timings as a table per repo or per step,
+// sortable by any column to find the slow repos and operations
+type TimingModel struct {
+	config  *Config
+	mode    timingMode
+	sortCol int
+	desc    bool
+	offset  int
+	// the run shown, taken when the view opens so a background refresh
+	// can't change the columns under sortCol
+	run timingRun
+}
+
+func NewTiming(config *Config) TimingModel {
+	return TimingModel{
+		config: config,
+		desc:   true,
+	}
+}
+
+// sorts by the total column, slowest first, each time the view is opened
+func (m TimingModel) reset() TimingModel {
+	m.run = m.config.timings
+	m.sortCol = len(m.header()) - 1
+	if m.mode == byStep {
+		m.sortCol = 2
+	}
+	m.desc = true
+	m.offset = 0
+	return m
+}
+
+func (m TimingModel) header() []string {
+	if m.mode == byStep {
+		return []string{"step", "repos", "total", "avg", "max", "slowest repo"}
+	}
+	return append(append([]string{"repo"}, m.run.steps()...), "total")
+}
+
+func (m TimingModel) rows() [][]timingCell {
+	run := m.run
+	steps := run.steps()
+	var rows [][]timingCell
+
+	if m.mode == byStep {
+		for _, step := range steps {
+			var total, slowest time.Duration
+			var count int
+			var slowRepo string
+			for _, t := range run.timings {
+				if t.Step != step {
+					continue
+				}
+				count++
+				total += t.Elapsed
+				if t.Elapsed > slowest {
+					slowest, slowRepo = t.Elapsed, t.Repo
+				}
+			}
+			rows = append(rows, []timingCell{
+				{text: step},
+				{text: fmt.Sprintf("%d", count), value: float64(count)},
+				msCell(total),
+				msCell(total / time.Duration(count)),
+				msCell(slowest),
+				{text: slowRepo},
+			})
+		}
+		return rows
+	}
+
+	byName := map[string]map[string]time.Duration{}
+	var names []string
+	for _, t := range run.timings {
+		if _, ok := byName[t.Repo]; !ok {
+			byName[t.Repo] = map[string]time.Duration{}
+			names = append(names, t.Repo)
+		}
+		byName[t.Repo][t.Step] += t.Elapsed
+	}
+	for _, name := range names {
+		row := []timingCell{{text: strings.TrimPrefix(name, m.config.Prefix)}}
+		var total time.Duration
+		for _, step := range steps {
+			row = append(row, msCell(byName[name][step]))
+			total += byName[name][step]
+		}
+		rows = append(rows, append(row, msCell(total)))
+	}
+	return rows
+}
+
+func (m TimingModel) sortedRows() [][]timingCell {
+	rows := m.rows()
+	col := m.sortCol
+	slices.SortStableFunc(rows, func(a, b []timingCell) int {
+		var c int
+		if col == 0 || (a[col].value == 0 && b[col].value == 0) {
+			c = strings.Compare(a[col].text, b[col].text)
+		} else if a[col].value < b[col].value {
+			c = -1
+		} else if a[col].value > b[col].value {
+			c = 1
+		}
+		if m.desc {
+			return -c
+		}
+		return c
+	})
+	return rows
+}
+
+func (m TimingModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TimingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		cols := len(m.header())
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc", "t":
+			m.config.state = homeView
+			return m, tea.ClearScreen
+		case "tab":
+			m.mode = (m.mode + 1) % 2
+			m = m.reset()
+		case "left", "h":
+			m.sortCol = positiveMod(m.sortCol-1, cols)
+		case "right", "l":
+			m.sortCol = positiveMod(m.sortCol+1, cols)
+		case "enter", " ", "r":
+			m.desc = !m.desc
+		case "up", "k":
+			m.offset = max(m.offset-1, 0)
+		case "down", "j":
+			m.offset = min(m.offset+1, max(len(m.rows())-historyPage, 0))
+		}
+	}
+	return m, nil
+}
+
+func (m TimingModel) View() string {
+	run := m.run
+	if len(run.timings) == 0 {
+		return "No timings yet, reload or save in settings first.\n" +
+			helpStyle.Render("\nesc: back • q: exit\n")
+	}
+
+	mode := "per repo"
+	if m.mode == byStep {
+		mode = "per step"
+	}
+	s := fmt.Sprintf("Timings of the last %s at %s, %s\n\n", run.op, run.at.Format(time.DateTime), mode)
+
+	header := m.header()
+	rows := m.sortedRows()
+	widths := make([]int, len(header))
+	for i, title := range header {
+		widths[i] = len(title) + 2
+		for _, row := range rows {
+			widths[i] = max(widths[i], len(row[i].text))
+		}
+	}
+
+	for i, title := range header {
+		if i == m.sortCol {
+			arrow := "▲"
+			if m.desc {
+				arrow = "▼"
+			}
+			title = selectedStyle.Render(fmt.Sprintf("%s %s", title, arrow))
+			title += strings.Repeat(" ", max(widths[i]-len(header[i])-2, 0))
+		} else {
+			title = fmt.Sprintf("%-*s", widths[i], title)
+		}
+		s += title + "  "
+	}
+	s += "\n"
+
+	end := min(m.offset+historyPage, len(rows))
+	for _, row := range rows[m.offset:end] {
+		for i, cell := range row {
+			if i == 0 || i == len(row)-1 && m.mode == byStep {
+				s += fmt.Sprintf("%-*s  ", widths[i], cell.text)
+			} else {
+				s += fmt.Sprintf("%*s  ", widths[i], cell.text)
+			}
+		}
+		s += "\n"
+	}
+
+	s += helpStyle.Render("\nhl: sort column • enter: reverse • jk: scroll • tab: per repo/step • esc: back\n")
+	return s
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type valueSource string
+
+const (
+	sourceDefault   valueSource = "default"
+	sourceUser      valueSource = "user"
+	sourceWorkspace valueSource = "workspace"
+	sourceEnv       valueSource = "env"
+	sourceFlag      valueSource = "flag"
+)
+
+const (
+	defaultBranch = "master"
+	defaultCols   = 4
+)
+
+// the settings that can come from more than one layer, a zero value means
+// the layer leaves it unset
+type layeredSettings struct {
+	Branch string `json:"branch,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Cols   int    `json:"cols,omitempty"`
+}
+
+type Theme struct {
+	Border   string `json:"border,omitempty"`
+	Focused  string `json:"focused,omitempty"`
+	Selected string `json:"selected,omitempty"`
+	Help     string `json:"help,omitempty"`
+}
+
+// UserConfig holds defaults shared by every workspace, workspace values
+// override it and env vars and flags override both
+type UserConfig struct {
+	layeredSettings
+	Theme Theme `json:"theme"`
+	// maps a pressed key to the built in key it acts as, e.g. {"w": "up"}
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+// $XDG_CONFIG_HOME/massgit/config.json or the platform equivalent
+func userConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "massgit", "config.json"), nil
+}
+
+func getUserConfig() UserConfig {
+	var user UserConfig
+	path, err := userConfigPath()
+	if err != nil {
+		logDebug("no user config dir, err=%v", err)
+		return user
+	}
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return user
+	} else if err != nil {
+		logWarn("failed to read user config %s, err=%v", path, err)
+		return user
+	}
+	err = json.Unmarshal(bytes, &user)
+	if err != nil {
+		logWarn("ignoring unreadable user config %s, err=%v", path, err)
+		return UserConfig{}
+	}
+	return user
+}
+
+// reads MASSGIT_BRANCH, MASSGIT_PREFIX and MASSGIT_COLS
+func envSettings() layeredSettings {
+	var env layeredSettings
+	env.Branch = os.Getenv("MASSGIT_BRANCH")
+	env.Prefix = os.Getenv("MASSGIT_PREFIX")
+	if cols := os.Getenv("MASSGIT_COLS"); cols != "" {
+		n, err := strconv.Atoi(cols)
+		if err != nil {
+			logWarn("ignoring MASSGIT_COLS=%q, err=%v", cols, err)
+		} else {
+			env.Cols = n
+		}
+	}
+	return env
+}
+
+// resolves the layered settings into config, lowest to highest priority:
+// defaults, user config, workspace config, env, flags. The workspace's own
+// values are kept aside so overrides from other layers are never persisted.
+func applyLayers(config *Config, user UserConfig, flags layeredSettings) {
+	config.stored = layeredSettings{Branch: config.Branch, Prefix: config.Prefix, Cols: config.Cols}
+	config.sources = map[string]valueSource{}
+
+	layers := []struct {
+		source   valueSource
+		settings layeredSettings
+	}{
+		{sourceDefault, layeredSettings{Branch: defaultBranch, Cols: defaultCols}},
+		{sourceUser, user.layeredSettings},
+		{sourceWorkspace, config.stored},
+		{sourceEnv, envSettings()},
+		{sourceFlag, flags},
+	}
+	config.sources["prefix"] = sourceDefault
+	for _, layer := range layers {
+		if layer.settings.Branch != "" {
+			config.Branch = layer.settings.Branch
+			config.sources["branch"] = layer.source
+		}
+		if layer.settings.Prefix != "" {
+			config.Prefix = layer.settings.Prefix
+			config.sources["prefix"] = layer.source
+		}
+		if layer.settings.Cols > 0 {
+			config.Cols = layer.settings.Cols
+			config.sources["cols"] = layer.source
+		}
+	}
+}
+
+// called when a layered value is edited in the tui, it then belongs to the
+// workspace and is saved with it
+func (c *Config) setWorkspaceValue(name string) {
+	switch name {
+	case "branch":
+		c.stored.Branch = c.Branch
+	case "prefix":
+		c.stored.Prefix = c.Prefix
+	case "cols":
+		c.stored.Cols = c.Cols
+	}
+	if c.sources == nil {
+		c.sources = map[string]valueSource{}
+	}
+	c.sources[name] = sourceWorkspace
+}
+
+func (c Config) source(name string) valueSource {
+	if source, ok := c.sources[name]; ok {
+		return source
+	}
+	return sourceWorkspace
+}
+
+// the config as it should be written to .massgit, with values that came
+// from another layer replaced by what the workspace itself had
+func (c Config) persisted() Config {
+	if c.sources == nil {
+		return c
+	}
+	if c.source("branch") != sourceWorkspace {
+		c.Branch = c.stored.Branch
+	}
+	if c.source("prefix") != sourceWorkspace {
+		c.Prefix = c.stored.Prefix
+	}
+	if c.source("cols") != sourceWorkspace {
+		c.Cols = c.stored.Cols
+	}
+	return c
+}
+
+func applyTheme(theme Theme) {
+	if theme.Border != "" {
+		modelStyle = modelStyle.BorderForeground(lipgloss.Color(theme.Border))
+	}
+	if theme.Focused != "" {
+		focusedModelStyle = focusedModelStyle.BorderForeground(lipgloss.Color(theme.Focused))
+	}
+	if theme.Selected != "" {
+		selectedStyle = selectedStyle.Foreground(lipgloss.Color(theme.Selected))
+	}
+	if theme.Help != "" {
+		helpStyle = helpStyle.Foreground(lipgloss.Color(theme.Help))
+	}
+}
+
+var namedKeys = map[string]tea.KeyType{
+	"up":    tea.KeyUp,
+	"down":  tea.KeyDown,
+	"left":  tea.KeyLeft,
+	"right": tea.KeyRight,
+	"enter": tea.KeyEnter,
+	"esc":   tea.KeyEsc,
+	"tab":   tea.KeyTab,
+	" ":     tea.KeySpace,
+}
+
+// translates msg through the user's key bindings
+func remapKey(keys map[string]string, msg tea.KeyMsg) tea.KeyMsg {
+	target, ok := keys[msg.String()]
+	if !ok {
+		return msg
+	}
+	if keyType, ok := namedKeys[target]; ok {
+		return tea.KeyMsg{Type: keyType}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(target)}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// how long a repo has to be quiet before its status is rerun, so a
+	// checkout touching hundreds of files costs one git status
+	watchDebounce = 300 * time.Millisecond
+)
+
+// directories inside a working tree that are never worth watching
+var ignoredDirs = map[string]bool{
+	"target":       true,
+	"node_modules": true,
+	"build":        true,
+	"dist":         true,
+}
+
+// sent when files in a watched repo changed
+type watchMsg struct {
+	name string
+}
+
+// repoWatcher watches the working tree and the top of .git (HEAD, index,
+// MERGE_HEAD...) of each selected repo and reports which repos changed
+type repoWatcher struct {
+	fs      *fsnotify.Watcher
+	changed chan string
+	// closed by Close so run never blocks on a reader that is gone
+	done  chan struct{}
+	once  sync.Once
+	mu    sync.Mutex
+	repos map[string]bool
+}
+
+func newRepoWatcher() (*repoWatcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &repoWatcher{
+		fs:      fsw,
+		changed: make(chan string),
+		done:    make(chan struct{}),
+		repos:   map[string]bool{},
+	}
+	go w.run()
+	return w, nil
+}
+
+// watches the selected repos and stops watching the others
+func (w *repoWatcher) sync(repos []Repo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, repo := range repos {
+		if repo.Selected && !w.repos[repo.Name] {
+			err := w.addRepo(repo.Name)
+			if err != nil {
+				logWarn("failed to watch %s, err=%v", repo.Name, err)
+				continue
+			}
+			w.repos[repo.Name] = true
+		} else if !repo.Selected && w.repos[repo.Name] {
+			w.removeRepo(repo.Name)
+			delete(w.repos, repo.Name)
+		}
+	}
+}
+
+func (w *repoWatcher) addRepo(name string) error {
+	err := w.fs.Add(filepath.Join(name, ".git"))
+	if err != nil {
+		return err
+	}
+	return w.addTree(name)
+}
+
+// fsnotify isn't recursive, every directory of the tree is added
+func (w *repoWatcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || ignoredDirs[d.Name()]) {
+			return filepath.SkipDir
+		}
+		return w.fs.Add(path)
+	})
+}
+
+func (w *repoWatcher) removeRepo(name string) {
+	prefix := name + string(filepath.Separator)
+	for _, path := range w.fs.WatchList() {
+		if path == name || strings.HasPrefix(path, prefix) {
+			w.fs.Remove(path)
+		}
+	}
+}
+
+// the repo an event belongs to, repos are direct children of the workspace
+func repoOfPath(path string) string {
+	name, _, _ := strings.Cut(filepath.ToSlash(filepath.Clean(path)), "/")
+	return name
+}
+
+func (w *repoWatcher) run() {
+	defer close(w.changed)
+	pending := map[string]time.Time{}
+	ticker := time.NewTicker(watchDebounce / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if strings.HasSuffix(event.Name, ".lock") {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() &&
+					!strings.Contains(filepath.ToSlash(event.Name), "/.git") {
+					w.addTree(event.Name)
+				}
+			}
+			pending[repoOfPath(event.Name)] = time.Now()
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			logWarn("watch error, err=%v", err)
+		case now := <-ticker.C:
+			for name, last := range pending {
+				if now.Sub(last) >= watchDebounce {
+					delete(pending, name)
+					select {
+					case w.changed <- name:
+					case <-w.done:
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// waits for the next changed repo
+func (w *repoWatcher) wait() tea.Cmd {
+	return func() tea.Msg {
+		name, ok := <-w.changed
+		if !ok {
+			return nil
+		}
+		return watchMsg{name: name}
+	}
+}
+
+func (w *repoWatcher) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return w.fs.Close()
+}
+
+// reruns status for a single repo, cheaper than updateRepo
+func statusRepo(repo Repo, gen int) tea.Cmd {
+	invalidateCachedStatus(repo.Name)
+	return func() tea.Msg {
+		status, err := gitStatusV2(fmt.Sprintf("./%s", repo.Name))
+		if err == nil {
+			repo.setStatus(status)
+		}
+		repo.setError(err)
+		return refreshMsg{repos: []Repo{repo}, gen: gen, partial: true}
+	}
+}
@@ -8,10 +8,10 @@ func init() {
 	shell = "bash"
 	grep = "C:/Program Files/Git/usr/bin/grep.exe"
 	sed = "C:/Program Files/Git/usr/bin/sed.exe"
-	updateMvn = func(version string, lineNum string) []string {
+	updateMvn = func(tag string, version string, lineNum string) []string {
 		return []string{
 			"-i",
-			fmt.Sprintf("%ss#^\\([ \\t]*\\).*#\\1<version>%s</version>#", lineNum, version),
+			fmt.Sprintf("%ss#^\\([ \\t]*\\).*#\\1<%s>%s</%s>#", lineNum, tag, version, tag),
 			"pom.xml",
 		}
 	}
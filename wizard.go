@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type wizardStep uint
+
+const (
+	scanStep wizardStep = iota
+	pickStep
+	prefixStep
+	colsStep
+)
+
+// build files checked in order, the first one found names the build type
+var buildFiles = []struct {
+	file      string
+	buildType string
+}{
+	{"pom.xml", "maven"},
+	{"build.gradle", "gradle"},
+	{"build.gradle.kts", "gradle"},
+	{"package.json", "npm"},
+	{"go.mod", "go"},
+	{"Cargo.toml", "cargo"},
+}
+
+type wizardScanMsg struct {
+	repos []Repo
+	err   error
+}
+
+// WizardModel walks a new workspace through picking repos, the display
+// prefix and column count instead of starting on an empty grid
+type WizardModel struct {
+	config *Config
+	step   wizardStep
+	repos  []Repo
+	cursor int
+	prefix textinput.Model
+	cols   textinput.Model
+	err    error
+}
+
+func NewWizard(config *Config) WizardModel {
+	m := WizardModel{
+		config: config,
+		step:   scanStep,
+		prefix: textinput.New(),
+		cols:   textinput.New(),
+	}
+	m.prefix.CharLimit = 40
+	m.prefix.Width = 20
+
+	m.cols.Placeholder = "4"
+	m.cols.CharLimit = 1
+	m.cols.Width = 20
+	return m
+}
+
+func detectBuildType(repoPath string) string {
+	for _, build := range buildFiles {
+		if _, err := os.Stat(filepath.Join(repoPath, build.file)); err == nil {
+			return build.buildType
+		}
+	}
+	return "unknown"
+}
+
+// finds the repos in the workspace with their default branch and build type
+func scanWorkspace() tea.Msg {
+	names, err := findGitRepos(".")
+	if err != nil {
+		return wizardScanMsg{err: err}
+	}
+
+	repos := make([]Repo, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repoPath := fmt.Sprintf("./%s", name)
+			repos[i] = Repo{Name: name, Selected: true, BuildType: detectBuildType(repoPath)}
+			branch, err := gitDefaultBranch(repoPath)
+			if err != nil {
+				logWarn("failed to detect default branch for %s, err=%v", name, err)
+			}
+			repos[i].DefaultBranch = branch
+		}()
+	}
+	wg.Wait()
+	return wizardScanMsg{repos: repos}
+}
+
+// the longest prefix shared by all names, cut back to the last separator
+// so "svc-billing" and "svc-bills" suggest "svc-" rather than "svc-bill"
+func commonPrefix(names []string) string {
+	if len(names) < 2 {
+		return ""
+	}
+	prefix := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(name, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	cut := strings.LastIndexAny(prefix, "-_.")
+	return prefix[:cut+1]
+}
+
+// the default branch most of the picked repos use
+func mostCommonBranch(repos []Repo) string {
+	counts := map[string]int{}
+	best := ""
+	for _, repo := range repos {
+		if !repo.Selected || repo.DefaultBranch == "" {
+			continue
+		}
+		counts[repo.DefaultBranch]++
+		if counts[repo.DefaultBranch] > counts[best] || (counts[repo.DefaultBranch] == counts[best] && repo.DefaultBranch < best) {
+			best = repo.DefaultBranch
+		}
+	}
+	return best
+}
+
+func (m WizardModel) selectedNames() []string {
+	var names []string
+	for _, repo := range m.repos {
+		if repo.Selected {
+			names = append(names, repo.Name)
+		}
+	}
+	return names
+}
+
+func (m WizardModel) Init() tea.Cmd {
+	return scanWorkspace
+}
+
+// writes the picked setup to the config and loads the repos' state
+func (m WizardModel) finish() (WizardModel, tea.Cmd) {
+	cols, err := strconv.Atoi(m.cols.Value())
+	if err != nil || cols <= 0 {
+		cols = defaultCols
+	}
+
+	m.config.Repos = m.repos
+	m.config.VisibleRepos = []int{}
+	for i := range m.config.Repos {
+		if m.config.Repos[i].Selected {
+			m.config.VisibleRepos = append(m.config.VisibleRepos, i)
+		}
+	}
+	m.config.Prefix = m.prefix.Value()
+	m.config.setWorkspaceValue("prefix")
+	m.config.Cols = cols
+	m.config.setWorkspaceValue("cols")
+	if branch := mostCommonBranch(m.repos); branch != "" {
+		m.config.Branch = branch
+		m.config.setWorkspaceValue("branch")
+	}
+
+	ma := &MessageAccumulator{}
+	var wg sync.WaitGroup
+	for _, i := range m.config.VisibleRepos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			updateRepo(&m.config.Repos[i], ma, true)
+		}()
+	}
+	wg.Wait()
+	logDebug("%s", ma)
+	m.config.timings = ma.run("reload")
+
+	err = m.config.save()
+	if err != nil {
+		logError("failed to save the new workspace, err=%v", err)
+	}
+	logInfo("created workspace with %d of %d repos", len(m.config.VisibleRepos), len(m.repos))
+	m.config.state = homeView
+	return m, tea.ClearScreen
+}
+
+func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case wizardScanMsg:
+		m.repos, m.err = msg.repos, msg.err
+		m.step = pickStep
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.step {
+		case pickStep:
+			switch msg.String() {
+			case "q":
+				return m, tea.Quit
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case "down", "j":
+				if m.cursor < len(m.repos)-1 {
+					m.cursor++
+				}
+			case " ", "x":
+				if m.cursor < len(m.repos) {
+					m.repos[m.cursor].Selected = !m.repos[m.cursor].Selected
+				}
+			case "a":
+				all := len(m.selectedNames()) != len(m.repos)
+				for i := range m.repos {
+					m.repos[i].Selected = all
+				}
+			case "enter":
+				m.prefix.SetValue(commonPrefix(m.selectedNames()))
+				m.prefix.Focus()
+				m.step = prefixStep
+			}
+			return m, nil
+		case prefixStep:
+			if msg.String() == "enter" {
+				m.prefix.Blur()
+				m.cols.SetValue(strconv.Itoa(defaultCols))
+				m.cols.Focus()
+				m.step = colsStep
+				return m, nil
+			}
+			m.prefix, cmd = m.prefix.Update(msg)
+			return m, cmd
+		case colsStep:
+			if msg.String() == "enter" {
+				m.cols.Blur()
+				return m.finish()
+			}
+			m.cols, cmd = m.cols.Update(msg)
+			return m, cmd
+		}
+	case errMsg:
+		m.err = msg
+	}
+	return m, nil
+}
+
+func (m WizardModel) View() string {
+	var s string = "Welcome to massgit, let's set up this workspace.\n\n"
+	switch m.step {
+	case scanStep:
+		s += "scanning for git repos..."
+	case pickStep:
+		if m.err != nil {
+			s += fmt.Sprintf("failed to scan for repos: %v\n", m.err)
+		} else if len(m.repos) == 0 {
+			s += "no git repos found in this directory, they can be added later with r in settings\n"
+		} else {
+			s += "Pick the repos to manage:\n\n"
+		}
+		for i, repo := range m.repos {
+			checked := " "
+			if repo.Selected {
+				checked = "x"
+			}
+			s += fmt.Sprintf("%s [%s] %-30s %-10s %s\n", getCursor(Cursor{row: m.cursor}, i, 0), checked,
+				repo.Name, repo.DefaultBranch, repo.BuildType)
+		}
+		s += helpStyle.Render("\nspace: toggle • a: toggle all • enter: next • q: exit\n")
+	case prefixStep:
+		s += fmt.Sprintf(
+			"Project name prefix to hide:\n\n%s\n\n",
+			m.prefix.View(),
+		)
+		s += helpStyle.Render("\nenter: next\n")
+	case colsStep:
+		s += fmt.Sprintf(
+			"Number of columns to display:\n\n%s\n\n",
+			m.cols.View(),
+		)
+		s += helpStyle.Render("\nenter: finish\n")
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// walks up from dir to the nearest directory containing name, the way git
+// finds .git
+func findUp(dir string, name string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// picks the workspace root: the --workspace flag if given, otherwise the
+// nearest .massgit above the working directory. Only when there is none does
+// the working directory become a new workspace, and never from inside a git
+// repo since that would create a stray workspace next to a single repo.
+func resolveWorkspace(explicit string) (string, error) {
+	if explicit != "" {
+		root, err := filepath.Abs(explicit)
+		if err != nil {
+			return "", err
+		}
+		info, err := os.Stat(root)
+		if err != nil {
+			return "", err
+		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("workspace %s is not a directory", root)
+		}
+		return root, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if root, ok := findUp(cwd, configDir); ok {
+		return root, nil
+	}
+	if repo, ok := findUp(cwd, ".git"); ok {
+		return "", fmt.Errorf("no %s workspace found above %s and it is inside the git repo %s, run massgit from the directory holding your repos or pass --workspace", configDir, cwd, repo)
+	}
+	return cwd, nil
+}
+
+// makes root the working directory so .massgit and the repo paths, which
+// are all relative to the workspace, resolve against it
+func enterWorkspace(explicit string) (string, error) {
+	root, err := resolveWorkspace(explicit)
+	if err != nil {
+		return "", err
+	}
+	return root, os.Chdir(root)
+}